@@ -0,0 +1,406 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for writing or editing a comment
+// swagger:model CommentRequest
+type CommentRequest struct {
+	// Text of the comment
+	// required: true
+	Content string `json:"content"`
+
+	// ID of the comment being replied to
+	ParentID *uint64 `json:"parent_id"`
+}
+
+// Parameters for commenting on a news
+// swagger:parameters createComment
+type CreateCommentParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+
+	// Comment details
+	// in: body
+	// required: true
+	Body CommentRequest
+}
+
+// Request model for hiding or reporting a comment
+// swagger:model CommentReasonRequest
+type CommentReasonRequest struct {
+	// Reason for the action
+	Reason string `json:"reason"`
+}
+
+// Request model for resolving a comment report
+// swagger:model ResolveReportRequest
+type ResolveReportRequest struct {
+	// Action to take: dismiss, hide or delete
+	// required: true
+	Action string `json:"action"`
+}
+
+// Parameters for acting on a comment by ID
+// swagger:parameters updateComment deleteComment hideComment unhideComment reportComment
+type CommentIDParams struct {
+	// ID of the comment
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type CommentController struct {
+	CommentService service.CommentService
+}
+
+func NewCommentController(commentService service.CommentService) *CommentController {
+	return &CommentController{CommentService: commentService}
+}
+
+// swagger:route GET /api/news/{id}/comments comments getNewsComments
+// Returns the comment threads of a news article.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []Comment
+//	400: CommonError
+//	404: CommonError
+//	500: CommonError
+func (cc *CommentController) GetNewsComments(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	comments, err := cc.CommentService.GetCommentsForNews(viewer, newsID)
+	if err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+// swagger:route POST /api/news/{id}/comments comments createComment
+// Comments on a news article or replies to another comment.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: Comment
+//	400: CommonError
+//	404: CommonError
+//	429: CommonError
+func (cc *CommentController) CreateComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	var request CommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	comment, err := cc.CommentService.CreateComment(viewer.UserID, newsID, request.ParentID, request.Content)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// swagger:route PUT /api/comments/{id} comments updateComment
+// Edits a comment. Only the author can edit it.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: Comment
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (cc *CommentController) UpdateComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var request CommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	comment, err := cc.CommentService.UpdateComment(viewer, commentID, request.Content)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// swagger:route DELETE /api/comments/{id} comments deleteComment
+// Deletes a comment. Authors can delete their own comments and moderators any comment.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+func (cc *CommentController) DeleteComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	if err := cc.CommentService.DeleteComment(viewer, commentID); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// swagger:route PUT /api/comments/{id}/hide comments hideComment
+// Hides a comment from players. Moderators only.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+func (cc *CommentController) HideComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var request CommentReasonRequest
+	_ = c.ShouldBindJSON(&request)
+
+	if err := cc.CommentService.HideComment(viewer, commentID, request.Reason); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment hidden successfully"})
+}
+
+// swagger:route PUT /api/comments/{id}/unhide comments unhideComment
+// Makes a hidden comment visible again. Moderators only.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+func (cc *CommentController) UnhideComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	if err := cc.CommentService.UnhideComment(viewer, commentID); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment restored successfully"})
+}
+
+// swagger:route POST /api/comments/{id}/report comments reportComment
+// Reports a comment to the moderators.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: CommonSuccess
+//	400: CommonError
+//	404: CommonError
+//	409: CommonError
+func (cc *CommentController) ReportComment(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var request CommentReasonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := cc.CommentService.ReportComment(viewer.UserID, commentID, request.Reason); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment reported successfully"})
+}
+
+// swagger:route GET /api/comment-reports comments getCommentReports
+// Lists comment reports, optionally filtered by status. Moderators only.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []CommentReport
+//	403: CommonError
+//	500: CommonError
+func (cc *CommentController) GetReports(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+	if !viewer.IsModerator {
+		c.JSON(http.StatusForbidden, gin.H{"error": "ADMIN or MODERATOR access required"})
+		return
+	}
+
+	reports, err := cc.CommentService.GetReports(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// swagger:route PUT /api/comment-reports/{id}/resolve comments resolveCommentReport
+// Resolves a comment report by dismissing it or acting on the comment. Moderators only.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (cc *CommentController) ResolveReport(c *gin.Context) {
+	viewer, ok := commentViewer(c)
+	if !ok {
+		return
+	}
+
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var request ResolveReportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := cc.CommentService.ResolveReport(viewer, reportID, request.Action); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment report resolved successfully"})
+}
+
+// commentViewer reads the logged-in user, answering 401 when there is none.
+func commentViewer(c *gin.Context) (service.CommentViewer, bool) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return service.CommentViewer{}, false
+	}
+	return service.CommentViewer{
+		UserID:      userID,
+		IsModerator: slices.Contains(roles, "ADMIN") || slices.Contains(roles, "MODERATOR"),
+		Request:     requestInfo(c),
+	}, true
+}
+
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCommentNotFound), errors.Is(err, service.ErrReportNotFound), errors.Is(err, service.ErrNewsNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCommentForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrCommentRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrAlreadyReported):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameters for listing the email outbox
+// swagger:parameters getOutboxEmails
+type OutboxEmailListParams struct {
+	// Only emails with this delivery status (pending, sending, sent or dead)
+	// in: query
+	Status string `json:"status"`
+
+	// Only emails of this kind, e.g. register_confirmation
+	// in: query
+	Kind string `json:"kind"`
+
+	// Page number, starting at 1
+	// in: query
+	Page int `json:"page"`
+
+	// Number of results per page (max 100)
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
+// Parameters for retrieving or resending an outbox email by ID
+// swagger:parameters getOutboxEmail resendOutboxEmail
+type OutboxEmailIDParams struct {
+	// ID of the outbox email
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type EmailOutboxController struct {
+	EmailOutboxService service.EmailOutboxService
+}
+
+func NewEmailOutboxController(emailOutboxService service.EmailOutboxService) *EmailOutboxController {
+	return &EmailOutboxController{EmailOutboxService: emailOutboxService}
+}
+
+// swagger:route GET /api/emails emails getOutboxEmails
+// Lists the emails of the outbox with their delivery status, newest first.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: OutboxPage
+//	400: CommonError
+//	403: CommonError
+func (ec *EmailOutboxController) GetEmails(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	filter := repository.OutboxFilter{Status: c.Query("status"), Kind: c.Query("kind")}
+	if filter.Status != "" && !enums.IsValidEmailStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status parameter"})
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	emails, err := ec.EmailOutboxService.GetEmails(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, emails)
+}
+
+// swagger:route GET /api/emails/{id} emails getOutboxEmail
+// Returns an outbox email with its content and delivery status.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: OutboxEmail
+//	403: CommonError
+//	404: CommonError
+func (ec *EmailOutboxController) GetEmail(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email ID"})
+		return
+	}
+
+	outboxEmail, err := ec.EmailOutboxService.GetEmail(id)
+	if err != nil {
+		c.JSON(outboxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, outboxEmail)
+}
+
+// swagger:route POST /api/emails/{id}/resend emails resendOutboxEmail
+// Queues an email that could not be delivered again.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: OutboxEmail
+//	403: CommonError
+//	404: CommonError
+//	409: CommonError
+func (ec *EmailOutboxController) ResendEmail(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email ID"})
+		return
+	}
+
+	outboxEmail, err := ec.EmailOutboxService.ResendEmail(id)
+	if err != nil {
+		c.JSON(outboxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, outboxEmail)
+}
+
+func outboxErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrEmailNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrEmailNotResendable), errors.Is(err, service.ErrEmailLinkExpired):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
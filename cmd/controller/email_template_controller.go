@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"venecraft-back/cmd/email"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameters for previewing an email template
+// swagger:parameters previewEmailTemplate
+type EmailTemplatePreviewParams struct {
+	// Name of the template, e.g. register/user_response
+	// in: query
+	// required: true
+	Name string `json:"name"`
+
+	// Locale to render the template in; falls back to the default locale
+	// in: query
+	Locale string `json:"locale"`
+
+	// Response format: json (default), html or text
+	// in: query
+	Format string `json:"format"`
+}
+
+type EmailTemplateController struct {
+	EmailTemplateService service.EmailTemplateService
+}
+
+func NewEmailTemplateController(emailTemplateService service.EmailTemplateService) *EmailTemplateController {
+	return &EmailTemplateController{EmailTemplateService: emailTemplateService}
+}
+
+// swagger:route GET /api/email-templates emails getEmailTemplates
+// Lists the email templates with their locales and versions.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []TemplateInfo
+//	403: CommonError
+func (etc *EmailTemplateController) GetTemplates(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, etc.EmailTemplateService.GetTemplates())
+}
+
+// swagger:route GET /api/email-templates/preview emails previewEmailTemplate
+// Renders an email template with sample data.
+//
+// Produces:
+//   - application/json
+//   - text/html
+//   - text/plain
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: Rendered
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (etc *EmailTemplateController) PreviewTemplate(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	name := c.Query("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template name is required"})
+		return
+	}
+
+	rendered, err := etc.EmailTemplateService.PreviewTemplate(name, c.Query("locale"))
+	if err != nil {
+		if errors.Is(err, email.ErrTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(rendered.Text))
+	case "json":
+		c.JSON(http.StatusOK, rendered)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format parameter"})
+	}
+}
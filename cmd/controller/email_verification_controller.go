@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"venecraft-back/cmd/service"
+)
+
+// Request model for verifying an email address, with the token of the link or the address and code
+// swagger:model VerifyEmailRequest
+type VerifyEmailRequest struct {
+	// Token of the verification link
+	Token string `json:"token"`
+
+	// Address the code was sent to
+	// example: user@example.com
+	Email string `json:"email"`
+
+	// Six digit code sent with the link
+	// example: 482915
+	Code string `json:"code"`
+}
+
+// Parameters for verifying an email address
+// swagger:parameters verifyEmail
+type VerifyEmailParams struct {
+	// Verification details
+	// in: body
+	// required: true
+	Body VerifyEmailRequest
+}
+
+type EmailVerificationController struct {
+	EmailVerificationService service.EmailVerificationService
+}
+
+func NewEmailVerificationController(emailVerificationService service.EmailVerificationService) *EmailVerificationController {
+	return &EmailVerificationController{emailVerificationService}
+}
+
+// swagger:route POST /api/verify-email verification verifyEmail
+// Verifies an email address with the token of the emailed link, or with the address and emailed code.
+// Completes the registration request or email change the address was sent for.
+//
+// responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	409: CommonError
+//	500: CommonError
+func (vc *EmailVerificationController) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	var err error
+	if req.Token != "" {
+		err = vc.EmailVerificationService.VerifyToken(req.Token)
+	} else {
+		err = vc.EmailVerificationService.VerifyCode(req.Email, req.Code)
+	}
+	if err != nil {
+		c.JSON(verificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+func verificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrVerificationInvalid), errors.Is(err, service.ErrInvalidEmail):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrVerificationPending):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
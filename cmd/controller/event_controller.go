@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// websocketWriteTimeout bounds each write to a WebSocket client.
+const websocketWriteTimeout = 10 * time.Second
+
+// Parameters for opening an event stream
+// swagger:parameters streamEvents streamEventsWebSocket
+type EventStreamParams struct {
+	// Comma separated topics to receive (news-published, notification, moderation); every topic
+	// the user may receive when empty
+	// in: query
+	Topics string `json:"topics"`
+
+	// ID of the last event received, to resume after it. The Last-Event-ID header is also accepted.
+	// in: query
+	LastEventID uint64 `json:"last_event_id"`
+
+	// Single-use ticket from POST /api/events/ticket, for clients that cannot send the
+	// Authorization header
+	// in: query
+	Ticket string `json:"ticket"`
+}
+
+type EventController struct {
+	EventStreamService service.EventStreamService
+	upgrader           websocket.Upgrader
+}
+
+func NewEventController(eventStreamService service.EventStreamService) *EventController {
+	return &EventController{
+		EventStreamService: eventStreamService,
+		upgrader: websocket.Upgrader{
+			// Streams are authenticated with a token rather than cookies, like the rest of the API.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// swagger:route POST /api/events/ticket events createStreamTicket
+// Issues a short-lived, single-use ticket to open an event stream with the ticket query parameter,
+// for clients that cannot send the Authorization header such as browsers.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: StreamTicket
+//	401: CommonError
+//	500: CommonError
+func (ec *EventController) CreateStreamTicket(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	ticket, err := ec.EventStreamService.IssueTicket(userID, roles)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue the stream ticket"})
+		return
+	}
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// swagger:route GET /api/events events streamEvents
+// Opens a Server-Sent Events stream. Each event has its ID, its topic as the event name and its
+// data as JSON. A "reset" event is sent when events after Last-Event-ID are no longer available,
+// and the client should reload its state. Comments are sent as heartbeats.
+//
+// Produces:
+//   - text/event-stream
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: StringResponse
+//	400: CommonError
+//	401: CommonError
+//	403: CommonError
+func (ec *EventController) StreamEvents(c *gin.Context) {
+	subscription, missed, resumed, ok := ec.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprint(c.Writer, "retry: 5000\n\n")
+	if !resumed {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range missed {
+		if err := writeServerSentEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(ec.EventStreamService.HeartbeatInterval())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, open := <-subscription.Events:
+			if !open {
+				// The client fell behind; it reconnects and resumes from its last event.
+				return
+			}
+			if err := writeServerSentEvent(c.Writer, event); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// swagger:route GET /api/events/ws events streamEventsWebSocket
+// Opens a WebSocket carrying the same events as the Server-Sent Events stream. Each message is a
+// JSON object with id, topic, data and time; a message with the "reset" topic asks the client to
+// reload its state. Ping frames are sent as heartbeats.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	400: CommonError
+//	401: CommonError
+//	403: CommonError
+func (ec *EventController) StreamEventsWebSocket(c *gin.Context) {
+	subscription, missed, resumed, ok := ec.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	conn, err := ec.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Error upgrading event stream: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	interval := ec.EventStreamService.HeartbeatInterval()
+	conn.SetReadDeadline(time.Now().Add(2 * interval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * interval))
+	})
+
+	// Client messages are not used, but reading processes pongs and notices the connection closing.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(event events.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+		return conn.WriteJSON(event)
+	}
+	if !resumed {
+		if err := write(events.Event{Topic: "reset", Time: time.Now()}); err != nil {
+			return
+		}
+	}
+	for _, event := range missed {
+		if err := write(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)); err != nil {
+				return
+			}
+		case event, open := <-subscription.Events:
+			if !open {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind, resume from the last event"),
+					time.Now().Add(websocketWriteTimeout))
+				return
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe reads the topics and last event ID of the request and subscribes the logged in user.
+// The error response is written when it fails.
+func (ec *EventController) subscribe(c *gin.Context) (*events.Subscription, []events.Event, bool, bool) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, nil, false, false
+	}
+
+	var topics []string
+	for _, topic := range strings.Split(c.Query("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid last event ID"})
+			return nil, nil, false, false
+		}
+	}
+
+	subscription, missed, resumed, err := ec.EventStreamService.Subscribe(userID, roles, topics, after)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidTopic):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrTopicForbidden):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return nil, nil, false, false
+	}
+	return subscription, missed, resumed, true
+}
+
+func writeServerSentEvent(w gin.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Topic, data)
+	return err
+}
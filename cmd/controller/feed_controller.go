@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	FeedService service.FeedService
+}
+
+func NewFeedController(feedService service.FeedService) *FeedController {
+	return &FeedController{FeedService: feedService}
+}
+
+// swagger:route GET /feeds/news.rss feeds getNewsRSS
+// Public RSS 2.0 feed of published news.
+//
+// Produces:
+//   - application/rss+xml
+//
+// Responses:
+//
+//	200: description: RSS document
+//	304: description: Not modified
+//	500: CommonError
+func (fc *FeedController) NewsRSS(c *gin.Context) {
+	fc.serveNewsFeed(c, "application/rss+xml; charset=utf-8", func(feed *service.NewsFeed) (string, error) {
+		return feed.Feed.ToRss()
+	})
+}
+
+// swagger:route GET /feeds/news.atom feeds getNewsAtom
+// Public Atom feed of published news.
+//
+// Produces:
+//   - application/atom+xml
+//
+// Responses:
+//
+//	200: description: Atom document
+//	304: description: Not modified
+//	500: CommonError
+func (fc *FeedController) NewsAtom(c *gin.Context) {
+	fc.serveNewsFeed(c, "application/atom+xml; charset=utf-8", func(feed *service.NewsFeed) (string, error) {
+		return feed.Feed.ToAtom()
+	})
+}
+
+// swagger:route GET /feeds/news.json feeds getNewsJSONFeed
+// Public JSON Feed of published news.
+//
+// Produces:
+//   - application/feed+json
+//
+// Responses:
+//
+//	200: description: JSON Feed document
+//	304: description: Not modified
+//	500: CommonError
+func (fc *FeedController) NewsJSON(c *gin.Context) {
+	fc.serveNewsFeed(c, "application/feed+json; charset=utf-8", func(feed *service.NewsFeed) (string, error) {
+		return feed.Feed.ToJSON()
+	})
+}
+
+// serveNewsFeed renders the feed, answering 304 when the client already has the current version.
+func (fc *FeedController) serveNewsFeed(c *gin.Context, contentType string, render func(*service.NewsFeed) (string, error)) {
+	feed, err := fc.FeedService.GetNewsFeed()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to build the news feed"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Header("ETag", feed.ETag)
+	c.Header("Last-Modified", feed.LastModified.Format(http.TimeFormat))
+
+	if notModified(c.Request, feed.ETag, feed.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, err := render(feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to render the news feed"})
+		return
+	}
+
+	if c.Request.Method == http.MethodHead {
+		c.Header("Content-Type", contentType)
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// notModified evaluates If-None-Match first and only falls back to If-Modified-Since when it is absent,
+// as required by RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		sinceTime, err := http.ParseTime(since)
+		return err == nil && !lastModified.After(sinceTime)
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameters for querying the audit log
+// swagger:parameters getAuditLogs
+type AuditLogListParams struct {
+	// Only entries of actions performed by this user
+	// in: query
+	UserID uint64 `json:"user_id"`
+
+	// Only entries of this action, e.g. news.update
+	// in: query
+	Action string `json:"action"`
+
+	// Only entries about this kind of record (user, register, news, comment or comment_report)
+	// in: query
+	TargetType string `json:"target_type"`
+
+	// Only entries about the record with this ID
+	// in: query
+	TargetID uint64 `json:"target_id"`
+
+	// Only entries recorded by the request with this X-Request-ID
+	// in: query
+	RequestID string `json:"request_id"`
+
+	// Only entries recorded at or after this time, in RFC3339
+	// in: query
+	From string `json:"from"`
+
+	// Only entries recorded before this time, in RFC3339
+	// in: query
+	To string `json:"to"`
+
+	// Page number, starting at 1
+	// in: query
+	Page int `json:"page"`
+
+	// Number of results per page (max 100)
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
+// Parameters for exporting the audit log
+// swagger:parameters exportAuditLogs
+type AuditLogExportParams struct {
+	// Format of the export: csv, json or jsonl (default json)
+	// in: query
+	Format string `json:"format"`
+
+	// Only entries recorded at or after this time, in RFC3339
+	// in: query
+	From string `json:"from"`
+
+	// Only entries recorded before this time, in RFC3339
+	// in: query
+	To string `json:"to"`
+
+	// Only entries of actions performed by this user
+	// in: query
+	UserID uint64 `json:"user_id"`
+
+	// Only entries of this action, e.g. news.update
+	// in: query
+	Action string `json:"action"`
+
+	// Only entries about this kind of record
+	// in: query
+	TargetType string `json:"target_type"`
+
+	// Only entries about the record with this ID
+	// in: query
+	TargetID uint64 `json:"target_id"`
+}
+
+// Parameters for retrieving an audit log entry by ID
+// swagger:parameters getAuditLog
+type AuditLogIDParams struct {
+	// ID of the log entry
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type LogController struct {
+	LogService service.LogService
+}
+
+func NewLogController(logService service.LogService) *LogController {
+	return &LogController{LogService: logService}
+}
+
+// swagger:route GET /api/audit-logs logs getAuditLogs
+// Queries the audit log, newest first.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: LogPage
+//	400: CommonError
+//	403: CommonError
+//	500: CommonError
+func (lc *LogController) GetLogs(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	logs, err := lc.LogService.GetLogs(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// swagger:route GET /api/audit-logs/{id} logs getAuditLog
+// Returns an audit log entry with the state of its target before and after the action.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: Log
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (lc *LogController) GetLog(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid log ID"})
+		return
+	}
+
+	entry, err := lc.LogService.GetLogByID(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrLogNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// swagger:route GET /api/audit-logs/verify logs verifyAuditLogs
+// Verifies the hash chain of the audit log and the signed checkpoints written to the storage.
+// The response lists the entries that were modified or removed.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: LogVerification
+//	403: CommonError
+//	500: CommonError
+func (lc *LogController) VerifyLogs(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	verification, err := lc.LogService.VerifyLogs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// swagger:route GET /api/audit-logs/export logs exportAuditLogs
+// Downloads the audit log entries of a date range as CSV, a JSON array or JSONL, oldest first.
+// The entries are streamed, so large ranges can be exported.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Produces:
+//   - application/json
+//   - application/x-ndjson
+//   - text/csv
+//
+// Responses:
+//
+//	200: []Log
+//	400: CommonError
+//	403: CommonError
+func (lc *LogController) ExportLogs(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", enums.LogFormatJSON)
+	if !enums.IsValidLogFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format parameter, expected csv, json or jsonl"})
+		return
+	}
+
+	c.Header("Content-Type", logExportContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-log.%s"`, format))
+	c.Status(http.StatusOK)
+	// The status is already sent once entries are streamed, so a failure can only cut the export short.
+	if err := lc.LogService.ExportLogs(filter, format, c.Writer); err != nil {
+		log.Printf("Error exporting the audit log: %v", err)
+	}
+}
+
+// swagger:route GET /api/audit-logs/archives logs getAuditLogArchives
+// Lists the archives of the audit log entries past their retention, with temporary download links.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []LogArchive
+//	403: CommonError
+//	500: CommonError
+func (lc *LogController) GetArchives(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	archives, err := lc.LogService.GetArchives(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, archives)
+}
+
+var logExportContentTypes = map[string]string{
+	enums.LogFormatJSON:  "application/json",
+	enums.LogFormatJSONL: "application/x-ndjson",
+	enums.LogFormatCSV:   "text/csv; charset=utf-8",
+}
+
+// parseLogFilter reads the audit log filters from the query string.
+func parseLogFilter(c *gin.Context) (repository.LogFilter, error) {
+	filter := repository.LogFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		RequestID:  c.Query("request_id"),
+	}
+	if filter.TargetType != "" && !enums.IsValidAuditTarget(filter.TargetType) {
+		return filter, errors.New("Invalid target_type parameter")
+	}
+
+	var err error
+	if value := c.Query("user_id"); value != "" {
+		if filter.UserID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, errors.New("Invalid user_id parameter")
+		}
+	}
+	if value := c.Query("target_id"); value != "" {
+		if filter.TargetID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, errors.New("Invalid target_id parameter")
+		}
+	}
+	if filter.From, err = parseOptionalTime(c.Query("from")); err != nil {
+		return filter, errors.New("Invalid from parameter, expected RFC3339")
+	}
+	if filter.To, err = parseOptionalTime(c.Query("to")); err != nil {
+		return filter, errors.New("Invalid to parameter, expected RFC3339")
+	}
+	return filter, nil
+}
+
+// auditActor describes the logged-in user and the request for the audit log.
+func auditActor(c *gin.Context) service.AuditActor {
+	userID, _, _ := middlewares.GetLoggedInUser(c)
+	return service.AuditActor{UserID: userID, RequestInfo: requestInfo(c)}
+}
+
+func requestInfo(c *gin.Context) service.RequestInfo {
+	return service.RequestInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: middlewares.GetRequestID(c),
+	}
+}
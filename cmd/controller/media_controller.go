@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameters for uploading an image to the media library
+// swagger:parameters uploadMedia
+type UploadMediaParams struct {
+	// Image file (JPEG, PNG, GIF or WebP)
+	// in: formData
+	// required: true
+	// swagger:file
+	File interface{} `json:"file"`
+}
+
+// Parameters for listing the media library
+// swagger:parameters getMedia
+type MediaListParams struct {
+	// Only media uploaded by this user
+	// in: query
+	UploadedBy uint64 `json:"uploaded_by"`
+
+	// Only media whose MIME type starts with this value (e.g. "image/")
+	// in: query
+	ContentType string `json:"content_type"`
+
+	// Page number, starting at 1
+	// in: query
+	Page int `json:"page"`
+
+	// Number of results per page (max 100)
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
+// Parameters for retrieving or deleting a media asset by ID
+// swagger:parameters getMediaByID deleteMedia
+type MediaIDParams struct {
+	// ID of the media asset
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type MediaController struct {
+	MediaService service.MediaService
+}
+
+func NewMediaController(mediaService service.MediaService) *MediaController {
+	return &MediaController{MediaService: mediaService}
+}
+
+// swagger:route POST /api/media media uploadMedia
+// Uploads an image to the media library. Uploading an image already in the library returns the existing asset.
+//
+// Consumes:
+//   - multipart/form-data
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: MediaItem
+//	400: CommonError
+//	403: CommonError
+//	413: CommonError
+func (mc *MediaController) UploadMedia(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	fileContent, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unable to open file"})
+		return
+	}
+	defer fileContent.Close()
+
+	media, err := mc.MediaService.UploadImage(c.Request.Context(), userID, fileContent)
+	if err != nil {
+		c.JSON(imageUploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// swagger:route GET /api/media media getMedia
+// Lists the media library, newest first.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: MediaPage
+//	403: CommonError
+//	500: CommonError
+func (mc *MediaController) GetMedia(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	filter := repository.MediaFilter{ContentType: c.Query("content_type")}
+	if uploadedBy := c.Query("uploaded_by"); uploadedBy != "" {
+		id, err := strconv.ParseUint(uploadedBy, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid uploaded_by parameter"})
+			return
+		}
+		filter.UploadedBy = id
+	}
+
+	page, pageSize := parsePagination(c)
+	media, err := mc.MediaService.GetMediaPage(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// swagger:route GET /api/media/{id} media getMediaByID
+// Returns a media asset with the news, servers and profiles that use it.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: MediaItem
+//	403: CommonError
+//	404: CommonError
+func (mc *MediaController) GetMediaByID(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	media, err := mc.MediaService.GetMedia(id)
+	if err != nil {
+		if errors.Is(err, service.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// swagger:route DELETE /api/media/{id} media deleteMedia
+// Deletes a media asset and its files. Assets still in use cannot be deleted.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+//	409: CommonError
+func (mc *MediaController) DeleteMedia(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	if err := mc.MediaService.DeleteMedia(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrMediaNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrMediaInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAnalyticsPeriod is the period covered when no range is requested.
+const defaultAnalyticsPeriod = 30 * 24 * time.Hour
+
+// Period filter for the news analytics
+// swagger:parameters getNewsAnalytics getNewsAnalyticsOverview
+type NewsAnalyticsParams struct {
+	// Start of the period, as a date (2006-01-02) or RFC3339. Defaults to 30 days before `to`.
+	// in: query
+	From string `json:"from"`
+
+	// End of the period, as a date (2006-01-02) or RFC3339. Defaults to now.
+	// in: query
+	To string `json:"to"`
+}
+
+// Parameters for the analytics of a single news
+// swagger:parameters getNewsAnalytics
+type NewsAnalyticsIDParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+// Parameters for the analytics across all news
+// swagger:parameters getNewsAnalyticsOverview
+type NewsAnalyticsOverviewParams struct {
+	// Number of news in the per-post breakdown (max 100)
+	// in: query
+	Limit int `json:"limit"`
+}
+
+type NewsAnalyticsController struct {
+	NewsAnalyticsService service.NewsAnalyticsService
+}
+
+func NewNewsAnalyticsController(newsAnalyticsService service.NewsAnalyticsService) *NewsAnalyticsController {
+	return &NewsAnalyticsController{NewsAnalyticsService: newsAnalyticsService}
+}
+
+// swagger:route GET /api/news/analytics news getNewsAnalyticsOverview
+// Returns views, reactions and comments across all news, with daily trends and a per-post breakdown.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsAnalyticsOverview
+//	400: CommonError
+//	403: CommonError
+func (nac *NewsAnalyticsController) GetAnalyticsOverview(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	from, to, err := parseAnalyticsPeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	overview, err := nac.NewsAnalyticsService.GetAnalyticsOverview(from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// swagger:route GET /api/news/{id}/analytics news getNewsAnalytics
+// Returns views, reactions and comments of a news post with daily trends.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsAnalytics
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (nac *NewsAnalyticsController) GetNewsAnalytics(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	from, to, err := parseAnalyticsPeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	analytics, err := nac.NewsAnalyticsService.GetNewsAnalytics(id, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// parseAnalyticsPeriod reads the from/to query parameters. A plain date as `to` covers the whole day.
+func parseAnalyticsPeriod(c *gin.Context) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseAnalyticsTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to parameter, expected a date or RFC3339")
+		}
+		to = parsed
+		if len(value) == len(time.DateOnly) {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+
+	from := to.Add(-defaultAnalyticsPeriod)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseAnalyticsTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from parameter, expected a date or RFC3339")
+		}
+		from = parsed
+	}
+	return from, to, nil
+}
+
+func parseAnalyticsTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
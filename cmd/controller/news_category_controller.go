@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for news category creation and update
+// swagger:model NewsCategoryRequest
+type NewsCategoryRequest struct {
+	// Display name of the category
+	// required: true
+	Name string `json:"name"`
+
+	// URL friendly identifier, generated from the name when empty
+	Slug string `json:"slug"`
+
+	// Short description of the category
+	Description string `json:"description"`
+}
+
+// Parameters for creating a news category
+// swagger:parameters createNewsCategory
+type CreateNewsCategoryParams struct {
+	// Category details
+	// in: body
+	// required: true
+	Body NewsCategoryRequest
+}
+
+// Parameters for updating or deleting a news category by ID
+// swagger:parameters updateNewsCategory deleteNewsCategory
+type NewsCategoryIDParams struct {
+	// ID of the category
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type NewsCategoryController struct {
+	NewsCategoryService service.NewsCategoryService
+}
+
+func NewNewsCategoryController(newsCategoryService service.NewsCategoryService) *NewsCategoryController {
+	return &NewsCategoryController{NewsCategoryService: newsCategoryService}
+}
+
+// swagger:route GET /api/news-categories news getNewsCategories
+// Returns all news categories.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []NewsCategory
+//	500: CommonError
+func (ncc *NewsCategoryController) GetAllCategories(c *gin.Context) {
+	categories, err := ncc.NewsCategoryService.GetAllCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// swagger:route POST /api/news-categories news createNewsCategory
+// Creates a news category.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: NewsCategory
+//	400: CommonError
+//	403: CommonError
+func (ncc *NewsCategoryController) CreateCategory(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request NewsCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	category := entity.NewsCategory{
+		Name:        request.Name,
+		Slug:        request.Slug,
+		Description: request.Description,
+	}
+	if err := ncc.NewsCategoryService.CreateCategory(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// swagger:route PUT /api/news-categories/{id} news updateNewsCategory
+// Updates a news category.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsCategory
+//	400: CommonError
+//	403: CommonError
+func (ncc *NewsCategoryController) UpdateCategory(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var request NewsCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	category, err := ncc.NewsCategoryService.UpdateCategory(id, &entity.NewsCategory{
+		Name:        request.Name,
+		Slug:        request.Slug,
+		Description: request.Description,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// swagger:route DELETE /api/news-categories/{id} news deleteNewsCategory
+// Deletes a news category. News in the category become uncategorized.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	403: CommonError
+func (ncc *NewsCategoryController) DeleteCategory(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := ncc.NewsCategoryService.DeleteCategory(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "News category deleted successfully"})
+}
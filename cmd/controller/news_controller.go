@@ -1,11 +1,13 @@
 package controller
 
 import (
-	"log"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"slices"
 	"strconv"
+	"strings"
+	"time"
 	"venecraft-back/cmd/entity"
 	"venecraft-back/cmd/middlewares"
 	"venecraft-back/cmd/service"
@@ -22,7 +24,7 @@ type CreateNewsRequest struct {
 	// required: true
 	Title string `json:"title"`
 
-	// Main content of the news
+	// Main content of the news, written in Markdown (CommonMark with tables)
 	// required: true
 	Content string `json:"content"`
 
@@ -30,9 +32,33 @@ type CreateNewsRequest struct {
 	// required: true
 	CreatedBy uint64 `json:"created_by"`
 
-	// Image for the news article
-	// required: true
+	// Image for the news article (JPEG, PNG, GIF or WebP). Metadata is stripped and
+	// medium/thumbnail variants are generated. Required unless media_id is given.
 	Image *multipart.FileHeader `form:"image"`
+
+	// ID of a media library asset to use as the image instead of uploading one
+	MediaID uint64 `json:"media_id"`
+
+	// Lifecycle status (draft, scheduled, published or archived). Defaults to published.
+	Status string `json:"status"`
+
+	// RFC3339 moment the news becomes visible. Required for scheduled news.
+	PublishAt string `json:"publish_at"`
+
+	// RFC3339 moment the news stops being visible
+	ExpiresAt string `json:"expires_at"`
+
+	// ID of the category the news belongs to
+	CategoryID uint64 `json:"category_id"`
+
+	// Comma separated list of tags
+	Tags string `json:"tags"`
+
+	// Whether the news is pinned to the top of the latest news
+	Pinned bool `json:"pinned"`
+
+	// Locale the title and content are written in. Defaults to the default locale.
+	Locale string `json:"locale"`
 }
 
 // Parameters for creating a news
@@ -45,7 +71,7 @@ type CreateNewsParams struct {
 }
 
 // Parameters for retrieving, updating, or deleting a news by ID
-// swagger:parameters getNewsByID updateNews deleteNews
+// swagger:parameters getNewsByID updateNews deleteNews publishNews archiveNews getNewsRevisions
 type UserNewsParams struct {
 	// ID of the news
 	// in: path
@@ -53,12 +79,137 @@ type UserNewsParams struct {
 	ID uint64 `json:"id"`
 }
 
+// Request model for news update. Omitted fields are left untouched.
+// swagger:model UpdateNewsRequest
+type UpdateNewsRequest struct {
+	// Title for the news article
+	Title *string `json:"title"`
+
+	// Markdown content of the news
+	Content *string `json:"content"`
+
+	// Image url for the news
+	ImageURL *string `json:"image_url"`
+
+	// ID of a media library asset to use as the image, taking precedence over image_url
+	MediaID *uint64 `json:"media_id"`
+
+	// Lifecycle status (draft, scheduled, published or archived)
+	Status *string `json:"status"`
+
+	// RFC3339 moment the news becomes visible, empty string to clear it
+	PublishAt *string `json:"publish_at"`
+
+	// RFC3339 moment the news stops being visible, empty string to clear it
+	ExpiresAt *string `json:"expires_at"`
+
+	// ID of the category, 0 to remove the category
+	CategoryID *uint64 `json:"category_id"`
+
+	// Full list of tags, replacing the current ones
+	Tags *[]string `json:"tags"`
+
+	// Whether the news is pinned to the top of the latest news
+	Pinned *bool `json:"pinned"`
+}
+
+// Parameters for updating a news
+// swagger:parameters updateNews
+type UpdateNewsParams struct {
+	// Fields to change
+	// in: body
+	// required: true
+	Body UpdateNewsRequest
+}
+
+// Parameters for retrieving or restoring a news revision
+// swagger:parameters getNewsRevision restoreNewsRevision
+type NewsRevisionParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+
+	// ID of the revision
+	// in: path
+	// required: true
+	RevisionID uint64 `json:"revisionID"`
+}
+
+func (r UpdateNewsRequest) toNewsUpdate() (service.NewsUpdate, error) {
+	update := service.NewsUpdate{
+		Title:      r.Title,
+		Content:    r.Content,
+		ImageURL:   r.ImageURL,
+		Status:     r.Status,
+		CategoryID: r.CategoryID,
+		Tags:       r.Tags,
+		Pinned:     r.Pinned,
+	}
+
+	if r.PublishAt != nil {
+		publishAt, err := parseOptionalTime(*r.PublishAt)
+		if err != nil {
+			return update, errors.New("invalid publish_at field, expected RFC3339")
+		}
+		update.PublishAt = publishAt
+		update.ClearPublishAt = publishAt == nil
+	}
+	if r.ExpiresAt != nil {
+		expiresAt, err := parseOptionalTime(*r.ExpiresAt)
+		if err != nil {
+			return update, errors.New("invalid expires_at field, expected RFC3339")
+		}
+		update.ExpiresAt = expiresAt
+		update.ClearExpiresAt = expiresAt == nil
+	}
+	return update, nil
+}
+
+// Locale negotiation for reading news
+// swagger:parameters getAllNews getLatestNews getNewsByID
+type NewsLocaleParams struct {
+	// Locale to serve the news in, overriding the user preference and Accept-Language
+	// in: query
+	Locale string `json:"locale"`
+}
+
+// Filters for listing news
+// swagger:parameters getAllNews getLatestNews
+type NewsListParams struct {
+	// Slug of the category to filter by
+	// in: query
+	Category string `json:"category"`
+
+	// Tag name to filter by
+	// in: query
+	Tag string `json:"tag"`
+}
+
+// Parameters for searching news
+// swagger:parameters searchNews
+type NewsSearchParams struct {
+	// Search terms. Supports quoted phrases, OR and -exclusions.
+	// in: query
+	// required: true
+	Q string `json:"q"`
+
+	// Page number, starting at 1
+	// in: query
+	Page int `json:"page"`
+
+	// Number of results per page (max 100)
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
 type NewsController struct {
-	NewsService service.NewsService
+	NewsService  service.NewsService
+	MediaService service.MediaService
 }
 
-func NewNewsController(newsService service.NewsService) *NewsController {
-	return &NewsController{NewsService: newsService}
+func NewNewsController(newsService service.NewsService, mediaService service.MediaService) *NewsController {
+	return &NewsController{NewsService: newsService, MediaService: mediaService}
 }
 
 // swagger:route POST /api/news news createNews
@@ -74,7 +225,7 @@ func NewNewsController(newsService service.NewsService) *NewsController {
 //	403: CommonError
 //	500: CommonError
 func (nc *NewsController) CreateNews(c *gin.Context) {
-	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
 	if !authenticated || !slices.Contains(roles, "ADMIN") {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
@@ -84,6 +235,7 @@ func (nc *NewsController) CreateNews(c *gin.Context) {
 	title := c.PostForm("title")
 	content := c.PostForm("content")
 	createdByStr := c.PostForm("created_by")
+	status := c.PostForm("status")
 
 	// Parse `created_by` as an integer
 	createdBy, err := strconv.ParseUint(createdByStr, 10, 64)
@@ -92,48 +244,67 @@ func (nc *NewsController) CreateNews(c *gin.Context) {
 		return
 	}
 
-	// Retrieve the image file
-	file, err := c.FormFile("image")
+	publishAt, err := parseOptionalTime(c.PostForm("publish_at"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at field, expected RFC3339"})
 		return
 	}
 
-	// Open the file to upload it to S3
-	fileContent, err := file.Open()
+	expiresAt, err := parseOptionalTime(c.PostForm("expires_at"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unable to open image file"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires_at field, expected RFC3339"})
 		return
 	}
-	defer func(fileContent multipart.File) {
-		err := fileContent.Close()
+
+	var categoryID *uint64
+	if categoryIDStr := c.PostForm("category_id"); categoryIDStr != "" {
+		parsed, err := strconv.ParseUint(categoryIDStr, 10, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Unable to close image file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id field"})
+			return
 		}
-	}(fileContent)
+		categoryID = &parsed
+	}
 
-	// Upload the image to S3
-	imageURL, err := utils.UploadFileToS3(fileContent, file.Filename)
+	pinned := false
+	if pinnedStr := c.PostForm("pinned"); pinnedStr != "" {
+		pinned, err = strconv.ParseBool(pinnedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pinned field"})
+			return
+		}
+	}
+
+	var tags []*entity.Tag
+	for _, tag := range strings.Split(c.PostForm("tags"), ",") {
+		tags = append(tags, &entity.Tag{Name: tag})
+	}
+
+	// Use an image from the media library or upload a new one
+	imageURL, err := nc.newsImageURL(c, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		c.JSON(imageUploadErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	// Create the news entity with the image URL
 	news := entity.News{
-		Title:     title,
-		Content:   content,
-		CreatedBy: createdBy,
-		ImageURL:  imageURL, // Assuming `ImageURL` is added to the News struct
+		Title:      title,
+		Content:    content,
+		CreatedBy:  createdBy,
+		ImageURL:   imageURL,
+		Status:     status,
+		PublishAt:  publishAt,
+		ExpiresAt:  expiresAt,
+		CategoryID: categoryID,
+		Tags:       tags,
+		Pinned:     pinned,
+		Locale:     c.PostForm("locale"),
 	}
 
 	// Attempt to create the news entry in the database
-	if err := nc.NewsService.CreateNews(&news); err != nil {
-		// If there is an error, delete the uploaded file from S3
-		deleteErr := utils.DeleteFileFromS3(file.Filename)
-		if deleteErr != nil {
-			log.Printf("Failed to delete file from S3 after error: %v", deleteErr)
-		}
+	// An image uploaded for a news that fails to be created is left to the media garbage collector
+	if err := nc.NewsService.CreateNews(auditActor(c), &news); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -152,13 +323,13 @@ func (nc *NewsController) CreateNews(c *gin.Context) {
 //	200: []News
 //	500: CommonError
 func (nc *NewsController) GetAllNews(c *gin.Context) {
-	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
 	if !authenticated {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	newsList, err := nc.NewsService.GetAllNews(userID)
+	newsList, err := nc.NewsService.GetAllNews(newsViewer(c, userID, roles), newsListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -177,13 +348,13 @@ func (nc *NewsController) GetAllNews(c *gin.Context) {
 //	200: []News
 //	500: CommonError
 func (nc *NewsController) GetLatestNews(c *gin.Context) {
-	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
 	if !authenticated {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	newsList, err := nc.NewsService.GetLatestNews(userID)
+	newsList, err := nc.NewsService.GetLatestNews(newsViewer(c, userID, roles), newsListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -191,6 +362,39 @@ func (nc *NewsController) GetLatestNews(c *gin.Context) {
 	c.JSON(http.StatusOK, newsList)
 }
 
+// swagger:route GET /api/news/search news searchNews
+// Searches news by title and content, ranked by relevance with highlighted snippets.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsSearchResult
+//	400: CommonError
+//	500: CommonError
+func (nc *NewsController) SearchNews(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The q parameter is required"})
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := nc.NewsService.SearchNews(newsViewer(c, userID, roles), query, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // swagger:route GET /api/news/{id} news getNewsByID
 // Returns a news article by its ID.
 //
@@ -204,7 +408,7 @@ func (nc *NewsController) GetLatestNews(c *gin.Context) {
 //	404: CommonError
 //	500: CommonError
 func (nc *NewsController) GetNewsByID(c *gin.Context) {
-	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
 	if !authenticated {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -216,7 +420,7 @@ func (nc *NewsController) GetNewsByID(c *gin.Context) {
 		return
 	}
 
-	news, err := nc.NewsService.GetNewsByID(userID, id)
+	news, err := nc.NewsService.GetNewsByID(newsViewer(c, userID, roles), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -230,38 +434,184 @@ func (nc *NewsController) GetNewsByID(c *gin.Context) {
 	c.JSON(http.StatusOK, news)
 }
 
-// swagger:route PUT /api/news news updateNews
-// Updates an existing news article.
+// swagger:route PUT /api/news/{id} news updateNews
+// Updates an existing news article. Only the fields present in the body are changed and every
+// change to the title, content or image is stored as a revision. Moderators can only edit visible
+// news and cannot change the status, publish_at or expires_at.
 //
 // Security:
 //   - BearerAuth: []
 //
 // Responses:
 //
-//	200: CommonSuccess
+//	200: News
 //	400: CommonError
 //	403: CommonError
+//	404: CommonError
 //	500: CommonError
 func (nc *NewsController) UpdateNews(c *gin.Context) {
-	var news entity.News
 	_, roles, authenticated := middlewares.GetLoggedInUser(c)
 	if !authenticated || !slices.Contains(roles, "ADMIN") && !slices.Contains(roles, "MODERATOR") {
-		c.JSON(http.StatusForbidden, gin.H{"error": " ADMIN ro MODERATOR access required"})
+		c.JSON(http.StatusForbidden, gin.H{"error": "ADMIN or MODERATOR access required"})
 		return
 	}
 
-	if err := c.ShouldBindJSON(&news); err != nil {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	var request UpdateNewsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
 
-	err := nc.NewsService.UpdateNews(&news)
+	isAdmin := slices.Contains(roles, "ADMIN")
+	if !isAdmin && (request.Status != nil || request.PublishAt != nil || request.ExpiresAt != nil) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required to change the status, publish_at or expires_at"})
+		return
+	}
+
+	update, err := request.toNewsUpdate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	update.IncludeUnpublished = isAdmin
+	if request.MediaID != nil {
+		media, err := nc.MediaService.GetImage(*request.MediaID)
+		if err != nil {
+			c.JSON(imageUploadErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		update.ImageURL = &media.URL
+	}
+
+	news, err := nc.NewsService.UpdateNews(auditActor(c), id, update)
+	if err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, news)
+}
+
+// swagger:route GET /api/news/{id}/revisions news getNewsRevisions
+// Lists the revisions of a news article, newest first.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []NewsRevision
+//	403: CommonError
+//	404: CommonError
+//	500: CommonError
+func (nc *NewsController) GetNewsRevisions(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") && !slices.Contains(roles, "MODERATOR") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "ADMIN or MODERATOR access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	revisions, err := nc.NewsService.GetNewsRevisions(id)
+	if err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// swagger:route GET /api/news/{id}/revisions/{revisionID} news getNewsRevision
+// Returns a single revision of a news article with its full content.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsRevision
+//	403: CommonError
+//	404: CommonError
+//	500: CommonError
+func (nc *NewsController) GetNewsRevision(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") && !slices.Contains(roles, "MODERATOR") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "ADMIN or MODERATOR access required"})
+		return
+	}
+
+	id, revisionID, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := nc.NewsService.GetNewsRevision(id, revisionID)
+	if err != nil {
+		if errors.Is(err, service.ErrRevisionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// swagger:route POST /api/news/{id}/revisions/{revisionID}/restore news restoreNewsRevision
+// Restores the title, content and image of a news article from a previous revision.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: News
+//	403: CommonError
+//	404: CommonError
+//	500: CommonError
+func (nc *NewsController) RestoreNewsRevision(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, revisionID, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	news, err := nc.NewsService.RestoreNewsRevision(auditActor(c), id, revisionID)
 	if err != nil {
+		if errors.Is(err, service.ErrRevisionNotFound) || errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "News updated successfully"})
+	c.JSON(http.StatusOK, news)
 }
 
 // swagger:route DELETE /api/news/{id} news deleteNews
@@ -274,15 +624,27 @@ func (nc *NewsController) UpdateNews(c *gin.Context) {
 //
 //	200: CommonSuccess
 //	400: CommonError
+//	403: CommonError
+//	404: CommonError
 //	500: CommonError
 func (nc *NewsController) DeleteNews(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") && !slices.Contains(roles, "MODERATOR") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "ADMIN or MODERATOR access required"})
+		return
+	}
+
 	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
 		return
 	}
 
-	if err := nc.NewsService.DeleteNews(id); err != nil {
+	if err := nc.NewsService.DeleteNews(auditActor(c), id); err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -290,8 +652,8 @@ func (nc *NewsController) DeleteNews(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "News deleted successfully"})
 }
 
-// swagger:route POST /api/news/{id}/reaction/{reactionType} news likeNews
-// Likes or dislikes a news post by ID.
+// swagger:route PUT /api/news/{id}/publish news publishNews
+// Publishes a draft or scheduled news article immediately.
 //
 // Security:
 //   - BearerAuth: []
@@ -300,37 +662,162 @@ func (nc *NewsController) DeleteNews(c *gin.Context) {
 //
 //	200: CommonSuccess
 //	400: CommonError
+//	403: CommonError
 //	500: CommonError
-func (nc *NewsController) ToggleReaction(c *gin.Context) {
-	userID, _, authenticated := middlewares.GetLoggedInUser(c)
-	if !authenticated {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+func (nc *NewsController) PublishNews(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
 
-	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
 		return
 	}
 
-	reactionType := c.Param("reactionType")
-	if reactionType != "like" && reactionType != "dislike" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reaction type"})
+	if err := nc.NewsService.PublishNews(auditActor(c), id); err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "News published successfully"})
+}
+
+// swagger:route PUT /api/news/{id}/archive news archiveNews
+// Archives a news article so players no longer see it.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	403: CommonError
+//	500: CommonError
+func (nc *NewsController) ArchiveNews(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 		return
 	}
 
-	// Toggle the reaction
-	reacted, err := nc.NewsService.ToggleReactionNews(userID, newsID, reactionType)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	if err := nc.NewsService.ArchiveNews(auditActor(c), id); err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Respond with the result of the toggle action
-	message := "News post reacted with " + reactionType
-	if !reacted {
-		message = "News post un-reacted " + reactionType
+	c.JSON(http.StatusOK, gin.H{"message": "News archived successfully"})
+}
+
+// newsViewer builds the visibility rules for the logged-in user. Only admins can see drafts.
+func newsViewer(c *gin.Context, userID uint64, roles []string) service.NewsViewer {
+	return service.NewsViewer{
+		UserID:             userID,
+		IncludeUnpublished: slices.Contains(roles, "ADMIN"),
+		Locale:             c.Query("locale"),
+		AcceptLanguage:     c.GetHeader("Accept-Language"),
+	}
+}
+
+// parseRevisionParams reads the news and revision IDs from the path, answering 400 when invalid.
+func parseRevisionParams(c *gin.Context) (uint64, uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return 0, 0, false
+	}
+	revisionID, err := strconv.ParseUint(c.Param("revisionID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return 0, 0, false
+	}
+	return id, revisionID, true
+}
+
+// newsListFilter reads the category and tag filters from the query string.
+func newsListFilter(c *gin.Context) service.NewsListFilter {
+	return service.NewsListFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+	}
+}
+
+// parseOptionalTime parses an RFC3339 form value, returning nil when it is empty.
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+var (
+	errImageRequired  = errors.New("an image file or media_id is required")
+	errInvalidMediaID = errors.New("invalid media_id field")
+)
+
+// newsImageURL returns the URL of the media library asset given as media_id, or uploads the image
+// file of the form to the media library.
+func (nc *NewsController) newsImageURL(c *gin.Context, uploaderID uint64) (string, error) {
+	if mediaIDStr := c.PostForm("media_id"); mediaIDStr != "" {
+		mediaID, err := strconv.ParseUint(mediaIDStr, 10, 64)
+		if err != nil {
+			return "", errInvalidMediaID
+		}
+		media, err := nc.MediaService.GetImage(mediaID)
+		if err != nil {
+			return "", err
+		}
+		return media.URL, nil
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return "", errImageRequired
+	}
+	fileContent, err := file.Open()
+	if err != nil {
+		return "", errImageRequired
+	}
+	defer fileContent.Close()
+
+	media, err := nc.MediaService.UploadImage(c.Request.Context(), uploaderID, fileContent)
+	if err != nil {
+		return "", err
+	}
+	return media.URL, nil
+}
+
+func imageUploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrMediaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, utils.ErrUnsupportedImage), errors.Is(err, utils.ErrInvalidImage), errors.Is(err, service.ErrMediaNotImage),
+		errors.Is(err, errImageRequired), errors.Is(err, errInvalidMediaID):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
-	c.JSON(http.StatusOK, gin.H{"message": message})
 }
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for creating or replacing a news translation
+// swagger:model NewsTranslationRequest
+type NewsTranslationRequest struct {
+	// Translated title
+	// required: true
+	Title string `json:"title"`
+
+	// Translated content, written in Markdown
+	// required: true
+	Content string `json:"content"`
+}
+
+// Parameters for listing the translations of a news
+// swagger:parameters getNewsTranslations
+type NewsTranslationsParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+// Parameters for saving or deleting a news translation
+// swagger:parameters saveNewsTranslation deleteNewsTranslation
+type NewsTranslationParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+
+	// Locale of the translation
+	// in: path
+	// required: true
+	Locale string `json:"locale"`
+}
+
+// Parameters for saving a news translation
+// swagger:parameters saveNewsTranslation
+type SaveNewsTranslationParams struct {
+	// Translated title and content
+	// in: body
+	// required: true
+	Body NewsTranslationRequest
+}
+
+type NewsTranslationController struct {
+	NewsTranslationService service.NewsTranslationService
+}
+
+func NewNewsTranslationController(newsTranslationService service.NewsTranslationService) *NewsTranslationController {
+	return &NewsTranslationController{NewsTranslationService: newsTranslationService}
+}
+
+// swagger:route GET /api/news/{id}/translations news getNewsTranslations
+// Lists the translations of a news article.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []NewsTranslation
+//	403: CommonError
+//	404: CommonError
+func (ntc *NewsTranslationController) GetTranslations(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	translations, err := ntc.NewsTranslationService.GetTranslations(id)
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// swagger:route PUT /api/news/{id}/translations/{locale} news saveNewsTranslation
+// Creates or replaces the translation of a news article for a locale.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NewsTranslation
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (ntc *NewsTranslationController) SaveTranslation(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	var request NewsTranslationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	translation, err := ntc.NewsTranslationService.SaveTranslation(userID, id, c.Param("locale"), request.Title, request.Content)
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// swagger:route DELETE /api/news/{id}/translations/{locale} news deleteNewsTranslation
+// Deletes the translation of a news article for a locale.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+func (ntc *NewsTranslationController) DeleteTranslation(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	if err := ntc.NewsTranslationService.DeleteTranslation(id, c.Param("locale")); err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+func translationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNewsNotFound), errors.Is(err, service.ErrTranslationNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parameters for listing notifications
+// swagger:parameters getMyNotifications
+type NotificationListParams struct {
+	// Only unread notifications
+	// in: query
+	Unread bool `json:"unread"`
+
+	// Page number, starting at 1
+	// in: query
+	Page int `json:"page"`
+
+	// Number of results per page (max 100)
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
+// Parameters for marking a notification as read
+// swagger:parameters markNotificationRead
+type NotificationIDParams struct {
+	// ID of the notification
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type NotificationController struct {
+	NotificationService service.NotificationService
+}
+
+func NewNotificationController(notificationService service.NotificationService) *NotificationController {
+	return &NotificationController{NotificationService: notificationService}
+}
+
+// swagger:route GET /api/me/notifications notifications getMyNotifications
+// Lists the notifications of the logged in user, newest first, with the number of unread ones.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: NotificationPage
+//	401: CommonError
+//	500: CommonError
+func (nc *NotificationController) GetMyNotifications(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	page, pageSize := parsePagination(c)
+	notifications, err := nc.NotificationService.GetNotifications(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+// swagger:route GET /api/me/notifications/unread-count notifications getUnreadNotificationCount
+// Returns the number of unread notifications of the logged in user, for the launcher badge.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	401: CommonError
+//	500: CommonError
+func (nc *NotificationController) GetUnreadCount(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	unread, err := nc.NotificationService.CountUnread(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread": unread})
+}
+
+// swagger:route POST /api/me/notifications/{id}/read notifications markNotificationRead
+// Marks a notification of the logged in user as read.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	401: CommonError
+//	404: CommonError
+//	500: CommonError
+func (nc *NotificationController) MarkRead(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := nc.NotificationService.MarkRead(userID, id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// swagger:route POST /api/me/notifications/read-all notifications markAllNotificationsRead
+// Marks every notification of the logged in user as read.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	401: CommonError
+//	500: CommonError
+func (nc *NotificationController) MarkAllRead(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	count, err := nc.NotificationService.MarkAllRead(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read", "count": count})
+}
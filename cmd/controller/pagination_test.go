@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query        string
+		wantPage     int
+		wantPageSize int
+	}{
+		{"", 1, defaultPageSize},
+		{"?page=3&page_size=50", 3, 50},
+		{"?page=0&page_size=0", 1, defaultPageSize},
+		{"?page=-2&page_size=-5", 1, defaultPageSize},
+		{"?page=abc&page_size=xyz", 1, defaultPageSize},
+		{"?page_size=1000", 1, maxPageSize},
+		{"?page=2&page_size=100", 2, maxPageSize},
+		{"?page=10001", maxPage, defaultPageSize},
+		{"?page=9223372036854775807&page_size=100", maxPage, maxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/news"+tt.query, nil)
+
+			page, pageSize := parsePagination(c)
+			assert.Equal(t, tt.wantPage, page)
+			assert.Equal(t, tt.wantPageSize, pageSize)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for reaction type creation
+// swagger:model CreateReactionTypeRequest
+type CreateReactionTypeRequest struct {
+	// Identifier used in the reaction endpoints
+	// required: true
+	Key string `json:"key"`
+
+	// Emoji shown for the reaction
+	// required: true
+	Emoji string `json:"emoji"`
+
+	// Human readable label
+	// required: true
+	Label string `json:"label"`
+
+	// Reactions sharing a group are mutually exclusive
+	ExclusiveGroup string `json:"exclusive_group"`
+
+	// Position of the reaction in the catalog
+	SortOrder int `json:"sort_order"`
+
+	// Whether the reaction can be added; defaults to true
+	Active *bool `json:"active"`
+}
+
+// Request model for reaction type update. Omitted fields are left untouched.
+// swagger:model UpdateReactionTypeRequest
+type UpdateReactionTypeRequest struct {
+	Emoji          *string `json:"emoji"`
+	Label          *string `json:"label"`
+	ExclusiveGroup *string `json:"exclusive_group"`
+	SortOrder      *int    `json:"sort_order"`
+	Active         *bool   `json:"active"`
+}
+
+// Parameters for toggling a reaction on a news post
+// swagger:parameters toggleNewsReaction
+type ToggleReactionParams struct {
+	// ID of the news
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+
+	// Key of the reaction type
+	// in: path
+	// required: true
+	ReactionType string `json:"reactionType"`
+}
+
+// Parameters for listing reaction types
+// swagger:parameters getReactionTypes
+type ReactionTypesParams struct {
+	// Include deactivated reaction types (admins only)
+	// in: query
+	IncludeInactive bool `json:"include_inactive"`
+}
+
+// Parameters for creating a reaction type
+// swagger:parameters createReactionType
+type CreateReactionTypeParams struct {
+	// Reaction type details
+	// in: body
+	// required: true
+	Body CreateReactionTypeRequest
+}
+
+// Parameters for updating or deleting a reaction type
+// swagger:parameters updateReactionType deleteReactionType
+type ReactionTypeKeyParams struct {
+	// Key of the reaction type
+	// in: path
+	// required: true
+	Key string `json:"key"`
+}
+
+// Parameters for updating a reaction type
+// swagger:parameters updateReactionType
+type UpdateReactionTypeParams struct {
+	// Fields to change
+	// in: body
+	// required: true
+	Body UpdateReactionTypeRequest
+}
+
+type ReactionController struct {
+	ReactionService service.ReactionService
+}
+
+func NewReactionController(reactionService service.ReactionService) *ReactionController {
+	return &ReactionController{ReactionService: reactionService}
+}
+
+// swagger:route POST /api/news/{id}/reaction/{reactionType} news toggleNewsReaction
+// Toggles a reaction from the catalog on a news post by ID. Reactions of the same exclusive group replace each other.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: ReactionToggleResult
+//	400: CommonError
+//	404: CommonError
+//	500: CommonError
+func (rc *ReactionController) ToggleReaction(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid news ID"})
+		return
+	}
+
+	result, err := rc.ReactionService.ToggleReaction(userID, newsID, c.Param("reactionType"))
+	if err != nil {
+		if errors.Is(err, service.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /api/reaction-types news getReactionTypes
+// Returns the reaction catalog.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []ReactionType
+//	500: CommonError
+func (rc *ReactionController) GetReactionTypes(c *gin.Context) {
+	_, roles, _ := middlewares.GetLoggedInUser(c)
+	includeInactive := c.Query("include_inactive") == "true" && slices.Contains(roles, "ADMIN")
+
+	reactionTypes, err := rc.ReactionService.GetReactionTypes(includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reactionTypes)
+}
+
+// swagger:route POST /api/reaction-types news createReactionType
+// Adds a reaction type to the catalog.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: ReactionType
+//	400: CommonError
+//	403: CommonError
+func (rc *ReactionController) CreateReactionType(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request CreateReactionTypeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	reactionType := entity.ReactionType{
+		Key:            request.Key,
+		Emoji:          request.Emoji,
+		Label:          request.Label,
+		ExclusiveGroup: request.ExclusiveGroup,
+		SortOrder:      request.SortOrder,
+		Active:         request.Active == nil || *request.Active,
+	}
+	if err := rc.ReactionService.CreateReactionType(&reactionType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reactionType)
+}
+
+// swagger:route PUT /api/reaction-types/{key} news updateReactionType
+// Updates a reaction type of the catalog.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: ReactionType
+//	400: CommonError
+//	403: CommonError
+//	404: CommonError
+func (rc *ReactionController) UpdateReactionType(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request UpdateReactionTypeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	reactionType, err := rc.ReactionService.UpdateReactionType(c.Param("key"), service.ReactionTypeUpdate{
+		Emoji:          request.Emoji,
+		Label:          request.Label,
+		ExclusiveGroup: request.ExclusiveGroup,
+		SortOrder:      request.SortOrder,
+		Active:         request.Active,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrReactionTypeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reactionType)
+}
+
+// swagger:route DELETE /api/reaction-types/{key} news deleteReactionType
+// Deletes a reaction type that has never been used.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	403: CommonError
+//	404: CommonError
+//	409: CommonError
+func (rc *ReactionController) DeleteReactionType(c *gin.Context) {
+	_, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	if err := rc.ReactionService.DeleteReactionType(c.Param("key")); err != nil {
+		switch {
+		case errors.Is(err, service.ErrReactionTypeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReactionTypeInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reaction type deleted successfully"})
+}
@@ -30,6 +30,9 @@ type RegisterRequest struct {
 	// required: true
 	// min length: 8
 	Password string `json:"password"`
+
+	// Preferred locale for the emails about the request, taken from Accept-Language when empty
+	Locale string `json:"locale"`
 }
 
 // Parameters for creating a registration request
@@ -41,6 +44,24 @@ type RegisterParams struct {
 	Body RegisterRequest
 }
 
+// Request model for resending the verification of a registration request
+// swagger:model ResendVerificationRequest
+type ResendVerificationRequest struct {
+	// Email address of the registration request
+	// required: true
+	// example: user@example.com
+	Email string `json:"email"`
+}
+
+// Parameters for resending the verification of a registration request
+// swagger:parameters resendVerification
+type ResendVerificationParams struct {
+	// Resend details
+	// in: body
+	// required: true
+	Body ResendVerificationRequest
+}
+
 // Parameters for approving or denying a registration request
 // swagger:parameters approveRegister denyRegister
 type RegisterActionParams struct {
@@ -59,12 +80,14 @@ func NewRegisterController(registerService service.RegisterService) *RegisterCon
 }
 
 // swagger:route POST /api/register register createRegister
-// Creates a registration request for a new user.
+// Creates a registration request for a new user. A link and code to verify the email address are
+// sent to it; the request reaches the admins once the address is verified.
 //
 // responses:
 //
 //	201: CommonSuccess
 //	400: CommonError
+//	409: CommonError
 //	500: CommonError
 func (rc *RegisterController) CreateRegister(c *gin.Context) {
 	var register entity.Register
@@ -73,13 +96,43 @@ func (rc *RegisterController) CreateRegister(c *gin.Context) {
 		return
 	}
 
+	// Emails about the request are sent in the browser's language unless one is given.
+	if register.Locale == "" {
+		register.Locale = c.GetHeader("Accept-Language")
+	}
+
 	err := rc.RegisterService.CreateRegister(&register)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(verificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Registration request created, check your email to verify it"})
+}
+
+// swagger:route POST /api/register/resend-verification register resendVerification
+// Sends a new verification link and code for a registration request awaiting verification.
+// Emails are throttled per address. The response is the same whether or not such a request
+// exists or an email was sent.
+//
+// responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	500: CommonError
+func (rc *RegisterController) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
+	if err := rc.RegisterService.ResendVerification(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resend verification"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Registration request created successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "If a registration request awaits verification, a new email was sent"})
 }
 
 // swagger:route GET /api/register register getAllRegisters
@@ -132,7 +185,7 @@ func (rc *RegisterController) ApproveRegister(c *gin.Context) {
 		return
 	}
 
-	user, err := rc.RegisterService.ApproveRegister(id)
+	user, err := rc.RegisterService.ApproveRegister(auditActor(c), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -165,7 +218,7 @@ func (rc *RegisterController) DenyRegister(c *gin.Context) {
 		return
 	}
 
-	err = rc.RegisterService.DenyRegister(id)
+	err = rc.RegisterService.DenyRegister(auditActor(c), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+	"venecraft-back/cmd/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for changing notification settings
+// swagger:model UpdateSettingsRequest
+type UpdateSettingsRequest struct {
+	// New values by setting key; settings left out keep their value
+	// required: true
+	// example: {"news_published": false, "security_alerts": true}
+	Settings map[string]bool `json:"settings" binding:"required"`
+}
+
+// Parameters for changing notification settings
+// swagger:parameters updateMySettings
+type UpdateSettingsParams struct {
+	// Settings to change
+	// in: body
+	// required: true
+	Body UpdateSettingsRequest
+}
+
+// Parameters for unsubscribing from a notification
+// swagger:parameters unsubscribe
+type UnsubscribeParams struct {
+	// Token of the unsubscribe link
+	// in: query
+	// required: true
+	Token string `json:"token"`
+}
+
+type SettingController struct {
+	SettingService service.SettingService
+}
+
+func NewSettingController(settingService service.SettingService) *SettingController {
+	return &SettingController{SettingService: settingService}
+}
+
+// swagger:route GET /api/me/settings settings getMySettings
+// Lists the notification settings of the logged in user. Settings the user never changed take the
+// default of their roles.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []UserSettingValue
+//	401: CommonError
+//	500: CommonError
+func (sc *SettingController) GetMySettings(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	settings, err := sc.SettingService.GetUserSettings(userID)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// swagger:route PUT /api/me/settings settings updateMySettings
+// Changes notification settings of the logged in user.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: []UserSettingValue
+//	400: CommonError
+//	401: CommonError
+//	500: CommonError
+func (sc *SettingController) UpdateMySettings(c *gin.Context) {
+	userID, _, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var request UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	settings, err := sc.SettingService.UpdateUserSettings(userID, request.Settings)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// swagger:route POST /api/unsubscribe settings unsubscribe
+// Turns off the notification setting of an unsubscribe link. Mail clients call it directly for
+// one-click unsubscribes (RFC 8058).
+//
+// Responses:
+//
+//	200: CommonSuccess
+//	400: CommonError
+//	500: CommonError
+func (sc *SettingController) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+
+	key, err := sc.SettingService.Unsubscribe(token)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully", "setting": key})
+}
+
+func settingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUnknownSetting), errors.Is(err, utils.ErrInvalidUnsubscribeToken),
+		errors.Is(err, utils.ErrExpiredUnsubscribeToken):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
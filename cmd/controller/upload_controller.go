@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"venecraft-back/cmd/middlewares"
+	"venecraft-back/cmd/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request model for reserving a direct upload
+// swagger:model CreateUploadRequest
+type CreateUploadRequest struct {
+	// Original name of the file
+	FileName string `json:"file_name"`
+
+	// MIME type of the file (image/jpeg, image/png, image/gif, image/webp or application/zip)
+	// required: true
+	ContentType string `json:"content_type" binding:"required"`
+
+	// Size of the file in bytes
+	// required: true
+	Size int64 `json:"size" binding:"required"`
+
+	// Hex encoded SHA-256 of the file
+	// required: true
+	Hash string `json:"hash" binding:"required"`
+}
+
+// Parameters for reserving a direct upload
+// swagger:parameters createUpload
+type CreateUploadParams struct {
+	// File details
+	// in: body
+	// required: true
+	Body CreateUploadRequest
+}
+
+// Parameters for confirming a direct upload
+// swagger:parameters confirmUpload
+type UploadIDParams struct {
+	// ID of the upload slot
+	// in: path
+	// required: true
+	ID uint64 `json:"id"`
+}
+
+type UploadController struct {
+	UploadService service.UploadService
+}
+
+func NewUploadController(uploadService service.UploadService) *UploadController {
+	return &UploadController{UploadService: uploadService}
+}
+
+// swagger:route POST /api/uploads media createUpload
+// Reserves an upload slot. The file must then be sent to upload_url with the given method and
+// headers, and confirmed before the slot expires.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	201: UploadTicket
+//	400: CommonError
+//	403: CommonError
+//	413: CommonError
+func (uc *UploadController) CreateUpload(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request CreateUploadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	ticket, err := uc.UploadService.CreateUpload(c.Request.Context(), userID, service.UploadRequest{
+		FileName:    request.FileName,
+		ContentType: request.ContentType,
+		Size:        request.Size,
+		Hash:        request.Hash,
+	})
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// swagger:route POST /api/uploads/{id}/confirm media confirmUpload
+// Verifies the size, type and hash of an uploaded file and adds it to the media library.
+//
+// Security:
+//   - BearerAuth: []
+//
+// Responses:
+//
+//	200: MediaItem
+//	403: CommonError
+//	404: CommonError
+//	409: CommonError
+//	410: CommonError
+//	422: CommonError
+func (uc *UploadController) ConfirmUpload(c *gin.Context) {
+	userID, roles, authenticated := middlewares.GetLoggedInUser(c)
+	if !authenticated || !slices.Contains(roles, "ADMIN") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	media, err := uc.UploadService.ConfirmUpload(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound), errors.Is(err, service.ErrMediaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUploadIncomplete):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUploadExpired):
+		return http.StatusGone
+	case errors.Is(err, service.ErrUploadRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUploadTypeNotAllowed), errors.Is(err, service.ErrInvalidUploadSize),
+		errors.Is(err, service.ErrInvalidUploadHash):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -132,7 +132,7 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 	}
 
 	role := c.Query("role")
-	err := uc.UserService.CreateUser(&user, role)
+	err := uc.UserService.CreateUser(auditActor(c), &user, role)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user with this email already exists" {
@@ -192,7 +192,8 @@ func (uc *UserController) GetUserByID(c *gin.Context) {
 }
 
 // swagger:route PUT /api/users/{id} users updateUser
-// Updates user information.
+// Updates user information. A new email address is only applied once confirmed with the link or
+// code sent to it; email_change_pending is set in the response when one was sent.
 //
 // Security:
 //   - BearerAuth: []
@@ -201,6 +202,7 @@ func (uc *UserController) GetUserByID(c *gin.Context) {
 //
 //	200: CommonSuccess
 //	400: CommonError
+//	409: CommonError
 //	500: CommonError
 func (uc *UserController) UpdateUser(c *gin.Context) {
 	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -215,12 +217,19 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	err = uc.UserService.UpdateUser(userID, &userUpdate)
+	emailChangePending, err := uc.UserService.UpdateUser(auditActor(c), userID, &userUpdate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(verificationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	if emailChangePending {
+		c.JSON(http.StatusOK, gin.H{
+			"message":              "User updated successfully, check the new email address to confirm the change",
+			"email_change_pending": true,
+		})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
 }
 
@@ -242,7 +251,7 @@ func (uc *UserController) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = uc.UserService.DeleteUser(id)
+	err = uc.UserService.DeleteUser(auditActor(c), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
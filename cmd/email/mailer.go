@@ -0,0 +1,104 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"venecraft-back/cmd/utils"
+)
+
+var ErrNoRecipients = errors.New("email has no recipients")
+
+// Message is an email ready to be sent by a Mailer.
+type Message struct {
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	Subject string
+	HTML    string
+	Text    string
+	// Headers are extra header fields, such as List-Unsubscribe
+	Headers map[string]string
+}
+
+// Recipients returns every address the message is delivered to.
+func (m *Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+	return append(recipients, m.Bcc...)
+}
+
+func (m *Message) validate() error {
+	if _, err := mail.ParseAddress(m.From); err != nil {
+		return fmt.Errorf("invalid sender %q: %v", m.From, err)
+	}
+	recipients := m.Recipients()
+	if len(recipients) == 0 {
+		return ErrNoRecipients
+	}
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid recipient %q: %v", recipient, err)
+		}
+	}
+	return nil
+}
+
+// Mailer delivers emails. Send returns the ID the transport assigned to the message.
+type Mailer interface {
+	Send(ctx context.Context, message *Message) (string, error)
+}
+
+// Senders are the addresses emails are sent from, by purpose.
+type Senders struct {
+	Support    string
+	Onboarding string
+	News       string
+}
+
+// LoadSenders reads the sender addresses from the environment.
+func LoadSenders() Senders {
+	return Senders{
+		Support:    utils.GetEnv("MAIL_FROM_SUPPORT", "Support <support@jjar.lat>"),
+		Onboarding: utils.GetEnv("MAIL_FROM_ONBOARDING", "Registration Service <onboarding@jjar.lat>"),
+		News:       utils.GetEnv("MAIL_FROM_NEWS", "Venecraft News <news@jjar.lat>"),
+	}
+}
+
+// NewMailer builds the transport selected by MAIL_DRIVER ("resend", "smtp", "file" or "log"), which
+// must be set explicitly so a missing API key never silently stops the delivery.
+func NewMailer() (Mailer, error) {
+	switch driver := utils.GetEnv("MAIL_DRIVER", ""); driver {
+	case "":
+		return nil, errors.New("MAIL_DRIVER must be set to resend, smtp, file or log")
+	case "resend":
+		apiKey := utils.GetEnv("RESEND_API_KEY", "")
+		if apiKey == "" {
+			return nil, errors.New("RESEND_API_KEY is required by the resend mail driver")
+		}
+		return NewResendMailer(apiKey), nil
+	case "smtp":
+		host := utils.GetEnv("SMTP_HOST", "")
+		if host == "" {
+			return nil, errors.New("SMTP_HOST is required by the smtp mail driver")
+		}
+		return NewSMTPMailer(SMTPConfig{
+			Host:     host,
+			Port:     utils.GetEnvInt("SMTP_PORT", 587),
+			Username: utils.GetEnv("SMTP_USERNAME", ""),
+			Password: utils.GetEnv("SMTP_PASSWORD", ""),
+		}), nil
+	case "file":
+		return NewFileMailer(utils.GetEnv("MAIL_SINK_DIR", "./mail"))
+	case "log":
+		log.Println("Emails are written to the log and not delivered (MAIL_DRIVER=log)")
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", driver)
+	}
+}
@@ -0,0 +1,134 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// newMessageID generates a unique Message-ID for the sender's domain.
+func newMessageID(from string) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+			domain = address.Address[at+1:]
+		}
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(token), domain), nil
+}
+
+// encodeMessage builds the RFC 5322 representation of the message. Bcc recipients are left out of
+// the headers.
+func encodeMessage(message *Message, messageID string) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+		}
+	}
+
+	header("Message-ID", messageID)
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("From", encodeAddress(message.From))
+	header("To", encodeAddressList(message.To))
+	header("Cc", encodeAddressList(message.Cc))
+	header("Reply-To", encodeAddress(message.ReplyTo))
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	names := make([]string, 0, len(message.Headers))
+	for name := range message.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header(textproto.CanonicalMIMEHeaderKey(name), message.Headers[name])
+	}
+	header("MIME-Version", "1.0")
+
+	switch {
+	case message.HTML != "" && message.Text != "":
+		writer := multipart.NewWriter(&buf)
+		header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", writer.Boundary()))
+		buf.WriteString("\r\n")
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain; charset=utf-8", message.Text},
+			{"text/html; charset=utf-8", message.HTML},
+		} {
+			partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {part.contentType},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQuotedPrintable(partWriter, part.body); err != nil {
+				return nil, err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		contentType, body := "text/html; charset=utf-8", message.HTML
+		if message.HTML == "" {
+			contentType, body = "text/plain; charset=utf-8", message.Text
+		}
+		header("Content-Type", contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func encodeAddress(address string) string {
+	if address == "" {
+		return ""
+	}
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.String()
+}
+
+func encodeAddressList(addresses []string) string {
+	encoded := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		encoded = append(encoded, encodeAddress(address))
+	}
+	return strings.Join(encoded, ", ")
+}
+
+// envelopeAddress returns the bare address used in the SMTP envelope.
+func envelopeAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Address, nil
+}
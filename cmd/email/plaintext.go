@@ -0,0 +1,116 @@
+package email
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+	lineSpacePattern  = regexp.MustCompile(`[ \t]*\n[ \t]*`)
+)
+
+// HTMLToText converts an HTML email into its plain text alternative. Paragraphs and other blocks
+// are separated by blank lines, list items are prefixed with a dash and links keep their URL.
+func HTMLToText(source string) string {
+	document, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return ""
+	}
+
+	var text strings.Builder
+	writeText(&text, document)
+
+	result := lineSpacePattern.ReplaceAllString(text.String(), "\n")
+	result = blankLinesPattern.ReplaceAllString(result, "\n\n")
+	return strings.TrimSpace(result)
+}
+
+func writeText(text *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		writeWords(text, node.Data)
+		return
+	case html.ElementNode:
+		switch node.DataAtom {
+		case atom.Head, atom.Style, atom.Script, atom.Title:
+			return
+		case atom.Br:
+			text.WriteString("\n")
+			return
+		case atom.Img:
+			return
+		case atom.Li:
+			text.WriteString("\n- ")
+		case atom.Td, atom.Th:
+			text.WriteString(" ")
+		}
+	}
+
+	block := isBlock(node)
+	if block {
+		text.WriteString("\n\n")
+	}
+	start := text.Len()
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeText(text, child)
+	}
+	if block {
+		text.WriteString("\n\n")
+	}
+
+	if node.DataAtom == atom.A {
+		href := attribute(node, "href")
+		linkText := strings.TrimSpace(text.String()[start:])
+		if href != "" && !strings.HasPrefix(href, "mailto:") && linkText != href {
+			text.WriteString(" (" + href + ")")
+		}
+	}
+}
+
+// writeWords writes text with its whitespace collapsed like a browser would.
+func writeWords(text *strings.Builder, data string) {
+	words := strings.Fields(data)
+	if len(words) == 0 {
+		if data != "" && !endsWithSpace(text) {
+			text.WriteString(" ")
+		}
+		return
+	}
+	if (data[0] == ' ' || data[0] == '\n' || data[0] == '\t') && !endsWithSpace(text) {
+		text.WriteString(" ")
+	}
+	text.WriteString(strings.Join(words, " "))
+	if last := data[len(data)-1]; last == ' ' || last == '\n' || last == '\t' {
+		text.WriteString(" ")
+	}
+}
+
+func endsWithSpace(text *strings.Builder) bool {
+	current := text.String()
+	return current == "" || strings.HasSuffix(current, " ") || strings.HasSuffix(current, "\n")
+}
+
+func isBlock(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+	switch node.DataAtom {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Ul, atom.Ol,
+		atom.Table, atom.Tr, atom.Blockquote, atom.Section, atom.Header, atom.Footer, atom.Hr, atom.Pre:
+		return true
+	}
+	return false
+}
+
+func attribute(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package email
+
+import (
+	"context"
+
+	"github.com/resend/resend-go/v2"
+)
+
+type resendMailer struct {
+	client *resend.Client
+}
+
+// NewResendMailer sends emails through the Resend API.
+func NewResendMailer(apiKey string) Mailer {
+	return &resendMailer{client: resend.NewClient(apiKey)}
+}
+
+func (m *resendMailer) Send(ctx context.Context, message *Message) (string, error) {
+	if err := message.validate(); err != nil {
+		return "", err
+	}
+
+	sent, err := m.client.Emails.SendWithContext(ctx, &resend.SendEmailRequest{
+		From:    message.From,
+		To:      message.To,
+		Cc:      message.Cc,
+		Bcc:     message.Bcc,
+		ReplyTo: message.ReplyTo,
+		Subject: message.Subject,
+		Html:    message.HTML,
+		Text:    message.Text,
+		Headers: message.Headers,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sent.Id, nil
+}
@@ -0,0 +1,71 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer writes every email as an .eml file to the directory instead of delivering it, so
+// they can be opened with any mail client during development.
+func NewFileMailer(dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create mail directory %s: %w", dir, err)
+	}
+	return &fileMailer{dir: dir}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, message *Message) (string, error) {
+	if err := message.validate(); err != nil {
+		return "", err
+	}
+	messageID, err := newMessageID(message.From)
+	if err != nil {
+		return "", err
+	}
+	data, err := encodeMessage(message, messageID)
+	if err != nil {
+		return "", err
+	}
+
+	// Bcc recipients are not part of the message, so they are recorded for inspection.
+	if len(message.Bcc) > 0 {
+		data = append([]byte("X-Bcc: "+strings.Join(message.Bcc, ", ")+"\r\n"), data...)
+	}
+
+	id := strings.Trim(messageID, "<>")
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405"), id[:strings.Index(id, "@")])
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0o644); err != nil {
+		return "", err
+	}
+	return messageID, nil
+}
+
+type logMailer struct{}
+
+// NewLogMailer writes the recipients and subject of every email to the log instead of delivering
+// it. The body is left out: it may hold password reset links and verification codes.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, message *Message) (string, error) {
+	if err := message.validate(); err != nil {
+		return "", err
+	}
+	messageID, err := newMessageID(message.From)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Email %s to %s: %q", messageID, strings.Join(message.Recipients(), ", "), message.Subject)
+	return messageID, nil
+}
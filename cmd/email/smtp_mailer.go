@@ -0,0 +1,128 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// smtpDialTimeout bounds the connection to the SMTP server when the context has no deadline.
+const smtpDialTimeout = 30 * time.Second
+
+// SMTPConfig configures the SMTP transport. Port 465 uses implicit TLS; other ports upgrade the
+// connection with STARTTLS when the server supports it.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer sends emails through an SMTP server.
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	return &smtpMailer{config: config}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message *Message) (string, error) {
+	if err := message.validate(); err != nil {
+		return "", err
+	}
+	messageID, err := newMessageID(message.From)
+	if err != nil {
+		return "", err
+	}
+	data, err := encodeMessage(message, messageID)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	defer client.Close()
+
+	if err := m.deliver(client, message, data); err != nil {
+		return "", err
+	}
+	return messageID, client.Quit()
+}
+
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var conn net.Conn
+	var err error
+	if m.config.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.config.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func (m *smtpMailer) deliver(client *smtp.Client, message *Message, data []byte) error {
+	from, err := envelopeAddress(message.From)
+	if err != nil {
+		return err
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range message.Recipients() {
+		address, err := envelopeAddress(recipient)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(address); err != nil {
+			return fmt.Errorf("recipient %s refused: %v", address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
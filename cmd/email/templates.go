@@ -0,0 +1,329 @@
+package email
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"venecraft-back/cmd/utils"
+)
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// dateLayouts formats the dates shown in emails for each locale. Other locales use English.
+var dateLayouts = map[string]string{
+	"en": "January 2, 2006 at 3:04 PM MST",
+	"es": "02/01/2006 15:04 MST",
+}
+
+// Rendered is an email template rendered for a locale.
+type Rendered struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Version string `json:"version"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// TemplateInfo describes an email template and the locales it is available in.
+type TemplateInfo struct {
+	Name     string            `json:"name"`
+	Locales  []string          `json:"locales"`
+	Versions map[string]string `json:"versions"`
+}
+
+type compiledTemplate struct {
+	template *template.Template
+	version  string
+}
+
+// Templates holds the email templates of every locale.
+//
+// The template directory contains a shared layout.html, shared partials under partials/, sample
+// data for previews under samples/ and one directory per locale. The partials/ directory of a
+// locale holds its own partials; the other files are templates named after their path, e.g.
+// es/register/user_response.html is the Spanish "register/user_response". A template
+// defines a "subject" and a "content" block, and optionally a "text" block replacing the plain
+// text generated from the HTML.
+type Templates struct {
+	fsys      fs.FS
+	templates map[string]map[string]*compiledTemplate
+}
+
+// DefaultTemplates loads the templates from TEMPLATE_PATH, or the ones embedded in the binary when
+// it is not set.
+func DefaultTemplates() (*Templates, error) {
+	if dir := utils.GetEnv("TEMPLATE_PATH", ""); dir != "" {
+		return LoadTemplates(os.DirFS(dir))
+	}
+	fsys, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	return LoadTemplates(fsys)
+}
+
+// LoadTemplates parses every template of the directory.
+func LoadTemplates(fsys fs.FS) (*Templates, error) {
+	shared, err := readSources(fsys, "layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email layout: %w", err)
+	}
+	partials, err := fs.Glob(fsys, "partials/*.html")
+	if err != nil {
+		return nil, err
+	}
+	partialSources, err := readSources(fsys, partials...)
+	if err != nil {
+		return nil, err
+	}
+	shared = append(shared, partialSources...)
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	localePartials := make(map[string][]templateSource)
+	var locales []string
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "partials" || entry.Name() == "samples" {
+			continue
+		}
+		files, err := fs.Glob(fsys, entry.Name()+"/partials/*.html")
+		if err != nil {
+			return nil, err
+		}
+		if localePartials[entry.Name()], err = readSources(fsys, files...); err != nil {
+			return nil, err
+		}
+		locales = append(locales, entry.Name())
+	}
+
+	t := &Templates{fsys: fsys, templates: make(map[string]map[string]*compiledTemplate)}
+	for _, locale := range locales {
+		// The partials of the default locale fill in the ones a locale does not translate.
+		sources := slices.Clone(shared)
+		if locale != utils.DefaultLocale() {
+			sources = append(sources, localePartials[utils.DefaultLocale()]...)
+		}
+		sources = append(sources, localePartials[locale]...)
+
+		err := fs.WalkDir(fsys, locale, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() && filePath == locale+"/partials" {
+				return fs.SkipDir
+			}
+			if entry.IsDir() || path.Ext(filePath) != ".html" {
+				return nil
+			}
+			source, err := readSources(fsys, filePath)
+			if err != nil {
+				return err
+			}
+			compiled, err := compileTemplate(locale, append(slices.Clone(sources), source...))
+			if err != nil {
+				return fmt.Errorf("failed to parse email template %s: %w", filePath, err)
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(filePath, locale+"/"), ".html")
+			if t.templates[name] == nil {
+				t.templates[name] = make(map[string]*compiledTemplate)
+			}
+			t.templates[name][locale] = compiled
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.templates) == 0 {
+		return nil, errors.New("no email templates found")
+	}
+	return t, nil
+}
+
+type templateSource struct {
+	path    string
+	content []byte
+}
+
+func readSources(fsys fs.FS, paths ...string) ([]templateSource, error) {
+	sources := make([]templateSource, 0, len(paths))
+	for _, filePath := range paths {
+		content, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, templateSource{path: filePath, content: content})
+	}
+	return sources, nil
+}
+
+// compileTemplate parses the layout, partials and template of a locale. The version is derived from
+// the sources, so any change to them yields a new version.
+func compileTemplate(locale string, sources []templateSource) (*compiledTemplate, error) {
+	root := template.New("layout").Funcs(templateFuncs(locale))
+	hash := sha256.New()
+	for i, source := range sources {
+		tmpl := root
+		if i > 0 {
+			tmpl = root.New(source.path)
+		}
+		if _, err := tmpl.Parse(string(source.content)); err != nil {
+			return nil, err
+		}
+		hash.Write([]byte(source.path))
+		hash.Write(source.content)
+	}
+
+	for _, block := range []string{"subject", "content"} {
+		if root.Lookup(block) == nil {
+			return nil, fmt.Errorf("missing %q block", block)
+		}
+	}
+	return &compiledTemplate{template: root, version: hex.EncodeToString(hash.Sum(nil))[:12]}, nil
+}
+
+func templateFuncs(locale string) template.FuncMap {
+	layout, ok := dateLayouts[locale]
+	if !ok {
+		layout = dateLayouts["en"]
+	}
+	return template.FuncMap{
+		"locale": func() string { return locale },
+		"datetime": func(value interface{}) (string, error) {
+			switch date := value.(type) {
+			case time.Time:
+				return date.UTC().Format(layout), nil
+			case *time.Time:
+				if date == nil {
+					return "", nil
+				}
+				return date.UTC().Format(layout), nil
+			case string:
+				parsed, err := time.Parse(time.RFC3339, date)
+				if err != nil {
+					return "", err
+				}
+				return parsed.UTC().Format(layout), nil
+			default:
+				return "", fmt.Errorf("datetime: unsupported value %T", value)
+			}
+		},
+	}
+}
+
+// Render renders the template in the requested locale, falling back to the default locale and
+// then to any locale the template exists in.
+func (t *Templates) Render(name, locale string, data interface{}) (*Rendered, error) {
+	variants, ok := t.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	locale = resolveTemplateLocale(variants, locale)
+	compiled := variants[locale]
+
+	var body bytes.Buffer
+	if err := compiled.template.ExecuteTemplate(&body, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render email template %s: %w", name, err)
+	}
+	subject, err := executeText(compiled.template, "subject", data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render subject of email template %s: %w", name, err)
+	}
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	text := HTMLToText(body.String())
+	if compiled.template.Lookup("text") != nil {
+		if text, err = executeText(compiled.template, "text", data); err != nil {
+			return nil, fmt.Errorf("failed to render text of email template %s: %w", name, err)
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	return &Rendered{
+		Name:    name,
+		Locale:  locale,
+		Version: compiled.version,
+		Subject: subject,
+		HTML:    body.String(),
+		Text:    text,
+	}, nil
+}
+
+// executeText executes a block meant for plain text, undoing the HTML escaping.
+func executeText(tmpl *template.Template, block string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", err
+	}
+	return html.UnescapeString(buf.String()), nil
+}
+
+func resolveTemplateLocale(variants map[string]*compiledTemplate, locale string) string {
+	for _, candidate := range []string{utils.NormalizeLocale(locale), utils.DefaultLocale()} {
+		if _, ok := variants[candidate]; ok {
+			return candidate
+		}
+	}
+	locales := make([]string, 0, len(variants))
+	for available := range variants {
+		locales = append(locales, available)
+	}
+	slices.Sort(locales)
+	return locales[0]
+}
+
+// List returns the available templates sorted by name.
+func (t *Templates) List() []TemplateInfo {
+	infos := make([]TemplateInfo, 0, len(t.templates))
+	for name, variants := range t.templates {
+		info := TemplateInfo{Name: name, Versions: make(map[string]string, len(variants))}
+		for locale, compiled := range variants {
+			info.Locales = append(info.Locales, locale)
+			info.Versions[locale] = compiled.version
+		}
+		slices.Sort(info.Locales)
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b TemplateInfo) int { return strings.Compare(a.Name, b.Name) })
+	return infos
+}
+
+// Sample returns the sample data of a template, used to preview it.
+func (t *Templates) Sample(name string) (map[string]interface{}, error) {
+	if _, ok := t.templates[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	data := make(map[string]interface{})
+	content, err := fs.ReadFile(t.fsys, "samples/"+name+".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("invalid sample data for email template %s: %w", name, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// swagger:model Comment
+type Comment struct {
+	// Comment ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the news the comment belongs to
+	// required: true
+	NewsID uint64 `json:"news_id" gorm:"index"`
+
+	// ID of the user who wrote the comment
+	// required: true
+	UserID uint64 `json:"user_id" gorm:"index"`
+
+	// ID of the comment this one replies to
+	ParentID *uint64 `json:"parent_id" gorm:"index"`
+
+	// Text of the comment
+	// required: true
+	Content string `json:"content" gorm:"type:text"`
+
+	// Whether a moderator hid the comment
+	Hidden bool `json:"hidden" gorm:"default:false"`
+
+	// ID of the moderator who hid the comment
+	HiddenBy *uint64 `json:"hidden_by,omitempty"`
+
+	// Reason given by the moderator for hiding the comment
+	HiddenReason string `json:"hidden_reason,omitempty" gorm:"type:varchar(255)"`
+
+	// Whether the comment was deleted. Deleted comments keep their place in the thread.
+	Deleted bool `json:"deleted" gorm:"default:false"`
+
+	// ID of the user who deleted the comment
+	DeletedBy *uint64 `json:"-"`
+
+	// Moment the author last edited the comment
+	EditedAt *time.Time `json:"edited_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP;index"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// swagger:model CommentReport
+type CommentReport struct {
+	// Report ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the reported comment
+	// required: true
+	CommentID uint64 `json:"comment_id" gorm:"uniqueIndex:idx_comment_report_reporter"`
+
+	// ID of the user who reported the comment
+	// required: true
+	ReporterID uint64 `json:"reporter_id" gorm:"uniqueIndex:idx_comment_report_reporter"`
+
+	// Reason given by the reporter
+	// required: true
+	Reason string `json:"reason" gorm:"type:varchar(255)"`
+
+	// Status of the report (open, resolved or dismissed)
+	// required: true
+	Status string `json:"status" gorm:"type:varchar(20);default:open;index"`
+
+	// ID of the moderator who handled the report
+	ResolvedBy *uint64 `json:"resolved_by"`
+
+	// Moment the report was handled
+	ResolvedAt *time.Time `json:"resolved_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
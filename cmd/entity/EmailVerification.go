@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// swagger:model EmailVerification
+type EmailVerification struct {
+	// Email verification ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// What the verification confirms (register or email_change)
+	// required: true
+	Purpose string `json:"purpose" gorm:"type:varchar(20);index:idx_email_verification_subject,priority:1"`
+
+	// ID of the registration request or user the address belongs to
+	// required: true
+	SubjectID uint64 `json:"subject_id" gorm:"index:idx_email_verification_subject,priority:2"`
+
+	// Address being verified
+	// required: true
+	Email string `json:"email" gorm:"type:varchar(255);index"`
+
+	// SHA-256 of the token sent in the verification link
+	TokenHash string `json:"-" gorm:"type:varchar(64);uniqueIndex"`
+
+	// SHA-256 of the code sent to be typed in
+	CodeHash string `json:"-" gorm:"type:varchar(64)"`
+
+	// Number of wrong codes entered
+	Attempts int `json:"attempts" gorm:"default:0"`
+
+	// Expiration of the link and code
+	// required: true
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Time the address was verified
+	ConsumedAt *time.Time `json:"consumed_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
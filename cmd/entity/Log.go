@@ -1,26 +1,57 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // swagger:model Log
 type Log struct {
 	// Log ID
 	// required: true
-	ID uint64 `gorm:"primaryKey;autoIncrement"`
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
 
-	// ID of the user associated with this log entry
+	// ID of the user who performed the action; 0 for actions taken by the system
 	// required: true
-	UserID uint64 `gorm:"index"`
+	UserID uint64 `json:"user_id" gorm:"index"`
 
-	// Action performed
+	// Action performed, e.g. news.update
 	// required: true
-	Action string `gorm:"type:varchar(255)"`
+	Action string `json:"action" gorm:"type:varchar(255);index"`
 
 	// Description of the action performed
 	// required: true
-	Description string `gorm:"type:varchar(255)"`
+	Description string `json:"description" gorm:"type:text"`
+
+	// Kind of record the action was performed on, e.g. news
+	TargetType string `json:"target_type,omitempty" gorm:"type:varchar(50);index:idx_log_target,priority:1"`
+
+	// ID of the record the action was performed on
+	TargetID uint64 `json:"target_id,omitempty" gorm:"index:idx_log_target,priority:2"`
+
+	// State of the record before the action, as JSON
+	Before json.RawMessage `json:"before,omitempty" gorm:"type:jsonb"`
+
+	// State of the record after the action, as JSON
+	After json.RawMessage `json:"after,omitempty" gorm:"type:jsonb"`
+
+	// IP address the request came from
+	IP string `json:"ip,omitempty" gorm:"type:varchar(45)"`
+
+	// User agent of the client that sent the request
+	UserAgent string `json:"user_agent,omitempty" gorm:"type:varchar(255)"`
+
+	// ID of the request, as sent back in the X-Request-ID header
+	RequestID string `json:"request_id,omitempty" gorm:"type:varchar(64);index"`
 
 	// Timestamp of the log entry
 	// required: true
-	Timestamp time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	Timestamp time.Time `json:"timestamp" gorm:"default:CURRENT_TIMESTAMP;index"`
+
+	// Hash of the previous entry in the chain; empty for the first entry
+	PrevHash string `json:"prev_hash" gorm:"type:varchar(64);not null;default:''"`
+
+	// SHA-256 of this entry and the previous hash, hex encoded
+	// required: true
+	Hash string `json:"hash" gorm:"type:varchar(64);not null;default:''"`
 }
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// swagger:model LogArchive
+type LogArchive struct {
+	// Archive ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Storage key of the compressed archive file
+	// required: true
+	StorageKey string `json:"storage_key" gorm:"type:varchar(512);unique"`
+
+	// Format of the archived entries (jsonl or csv)
+	// required: true
+	Format string `json:"format" gorm:"type:varchar(10)"`
+
+	// Number of entries in the archive
+	// required: true
+	Entries int `json:"entries"`
+
+	// ID of the first archived entry
+	// required: true
+	FirstLogID uint64 `json:"first_log_id" gorm:"index"`
+
+	// ID of the last archived entry
+	// required: true
+	LastLogID uint64 `json:"last_log_id"`
+
+	// Timestamp of the oldest archived entry
+	From time.Time `json:"from"`
+
+	// Timestamp of the newest archived entry
+	To time.Time `json:"to"`
+
+	// SHA-256 of the archive file, hex encoded
+	// required: true
+	SHA256 string `json:"sha256" gorm:"type:varchar(64)"`
+
+	// Place in the hash chain of every archived entry, so verification can tell the archived entries
+	// from deleted ones
+	Chain []LogArchiveEntry `json:"-" gorm:"serializer:json;type:jsonb"`
+
+	// Signature of the archive record, so the entries it removed from the hash chain are accounted for
+	// required: true
+	Signature string `json:"signature" gorm:"type:varchar(100)"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LogArchiveEntry is the place of an archived entry in the hash chain of the log.
+type LogArchiveEntry struct {
+	ID       uint64 `json:"id"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
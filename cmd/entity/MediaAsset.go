@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// swagger:model MediaAsset
+type MediaAsset struct {
+	// Media asset ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Storage key of the original file
+	// required: true
+	Key string `json:"key" gorm:"type:varchar(255);uniqueIndex"`
+
+	// Public URL of the original file
+	// required: true
+	URL string `json:"url" gorm:"type:varchar(512);index"`
+
+	// ID of the user who uploaded the asset
+	// required: true
+	UploadedBy uint64 `json:"uploaded_by" gorm:"index"`
+
+	// MIME type of the stored file
+	// required: true
+	ContentType string `json:"content_type" gorm:"type:varchar(100)"`
+
+	// Size of the original file in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// Width of the image in pixels
+	Width int `json:"width"`
+
+	// Height of the image in pixels
+	Height int `json:"height"`
+
+	// SHA-256 of the stored file
+	// required: true
+	Hash string `json:"hash" gorm:"type:varchar(64);index"`
+
+	// Upload timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP;index"`
+}
@@ -12,10 +12,16 @@ type News struct {
 	// required: true
 	Title string `json:"title" gorm:"type:varchar(255)"`
 
-	// Content of the news article
+	// Markdown source of the news article
 	// required: true
 	Content string `json:"content" gorm:"type:text"`
 
+	// Sanitized HTML rendered from the Markdown content
+	ContentHTML string `json:"content_html" gorm:"type:text"`
+
+	// Plain text summary generated from the content
+	Excerpt string `json:"excerpt" gorm:"type:varchar(500)"`
+
 	// ID of the user who created this news article
 	// required: true
 	CreatedBy uint64 `json:"created_by" gorm:"index"`
@@ -24,7 +30,36 @@ type News struct {
 	// required: true
 	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at"`
+
 	// Image url for the new
 	// required: true
 	ImageURL string `json:"image_url" gorm:"type:varchar(255)"`
+
+	// Locale the title and content are written in; translations cover the other locales
+	// required: true
+	Locale string `json:"locale" gorm:"type:varchar(10);default:es"`
+
+	// Lifecycle status of the news article (draft, scheduled, published or archived)
+	// required: true
+	Status string `json:"status" gorm:"type:varchar(20);default:published;index"`
+
+	// Category the news article belongs to
+	CategoryID *uint64 `json:"category_id" gorm:"index"`
+
+	// Category details
+	Category *NewsCategory `json:"category,omitempty" gorm:"constraint:OnDelete:SET NULL;"`
+
+	// Free-form tags attached to the news article
+	Tags []*Tag `json:"tags,omitempty" gorm:"many2many:news_tags;"`
+
+	// Pinned news always sort first in the latest news
+	Pinned bool `json:"pinned" gorm:"default:false;index"`
+
+	// Moment the news article becomes visible to players
+	PublishAt *time.Time `json:"publish_at" gorm:"index"`
+
+	// Moment the news article stops being visible to players
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index"`
 }
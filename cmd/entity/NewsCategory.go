@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// swagger:model NewsCategory
+type NewsCategory struct {
+	// Category ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Display name of the category
+	// required: true
+	Name string `json:"name" gorm:"type:varchar(100)"`
+
+	// URL friendly identifier used to filter news
+	// required: true
+	Slug string `json:"slug" gorm:"type:varchar(100);unique"`
+
+	// Short description of the category
+	Description string `json:"description" gorm:"type:varchar(255)"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
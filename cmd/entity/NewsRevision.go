@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// swagger:model NewsRevision
+type NewsRevision struct {
+	// Revision ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the revised news article
+	// required: true
+	NewsID uint64 `json:"news_id" gorm:"uniqueIndex:idx_news_revision_version"`
+
+	// Sequential version number within the news article, starting at 1
+	// required: true
+	Version int `json:"version" gorm:"uniqueIndex:idx_news_revision_version"`
+
+	// ID of the user who made the change
+	// required: true
+	EditedBy uint64 `json:"edited_by" gorm:"index"`
+
+	// Title after the change
+	// required: true
+	Title string `json:"title" gorm:"type:varchar(255)"`
+
+	// Markdown content after the change
+	// required: true
+	Content string `json:"content" gorm:"type:text"`
+
+	// Image url after the change
+	ImageURL string `json:"image_url" gorm:"type:varchar(255)"`
+
+	// JSON diff of title, content and image against the previous revision
+	Changes string `json:"changes" gorm:"type:text"`
+
+	// Revision this one was restored from, if it is a rollback
+	RestoredFrom *uint64 `json:"restored_from"`
+
+	// Timestamp of the change
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// swagger:model NewsTranslation
+type NewsTranslation struct {
+	// Translation ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the translated news article
+	// required: true
+	NewsID uint64 `json:"news_id" gorm:"uniqueIndex:idx_news_translation_locale"`
+
+	// Locale of the translation (e.g. "en")
+	// required: true
+	Locale string `json:"locale" gorm:"type:varchar(10);uniqueIndex:idx_news_translation_locale"`
+
+	// Translated title
+	// required: true
+	Title string `json:"title" gorm:"type:varchar(255)"`
+
+	// Translated Markdown content
+	// required: true
+	Content string `json:"content" gorm:"type:text"`
+
+	// Sanitized HTML rendered from the translated content
+	ContentHTML string `json:"content_html" gorm:"type:text"`
+
+	// Plain text summary generated from the translated content
+	Excerpt string `json:"excerpt" gorm:"type:varchar(500)"`
+
+	// ID of the user who last edited the translation
+	// required: true
+	UpdatedBy uint64 `json:"updated_by"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// swagger:model NewsView
+type NewsView struct {
+	// View ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the news that was read
+	// required: true
+	NewsID uint64 `json:"news_id" gorm:"uniqueIndex:idx_news_view_daily;index"`
+
+	// ID of the user who read the news
+	// required: true
+	UserID uint64 `json:"user_id" gorm:"uniqueIndex:idx_news_view_daily"`
+
+	// UTC day of the view; a user counts once per news and day
+	// required: true
+	ViewDate time.Time `json:"view_date" gorm:"type:date;uniqueIndex:idx_news_view_daily;index"`
+
+	// Moment of the first view that day
+	// required: true
+	ViewedAt time.Time `json:"viewed_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// swagger:model Notification
+type Notification struct {
+	// Notification ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// ID of the user the notification is for
+	// required: true
+	UserID uint64 `json:"user_id" gorm:"index:idx_notification_user,priority:1"`
+
+	// Kind of event, e.g. news_published
+	// required: true
+	Type string `json:"type" gorm:"type:varchar(50)"`
+
+	// Title in the user's locale
+	// required: true
+	Title string `json:"title" gorm:"type:varchar(255)"`
+
+	// Body in the user's locale
+	Body string `json:"body" gorm:"type:text"`
+
+	// IDs of the records the notification is about, e.g. {"news_id": 3}
+	Data map[string]interface{} `json:"data" gorm:"serializer:json;type:jsonb"`
+
+	// Time the user read the notification
+	ReadAt *time.Time `json:"read_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP;index:idx_notification_user,priority:2"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import "time"
+
+// swagger:model OutboxEmail
+type OutboxEmail struct {
+	// Outbox email ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Kind of email, the name of its template, e.g. register/user_confirmation
+	// required: true
+	Kind string `json:"kind" gorm:"type:varchar(100);index"`
+
+	// Locale the email was rendered in
+	Locale string `json:"locale" gorm:"type:varchar(10)"`
+
+	// Version of the template the email was rendered with
+	TemplateVersion string `json:"template_version" gorm:"type:varchar(20)"`
+
+	// Sender address
+	// required: true
+	From string `json:"from" gorm:"type:varchar(255)"`
+
+	// Recipient addresses
+	To []string `json:"to" gorm:"serializer:json;type:jsonb"`
+
+	// Carbon copy addresses
+	Cc []string `json:"cc" gorm:"serializer:json;type:jsonb"`
+
+	// Blind carbon copy addresses
+	Bcc []string `json:"bcc" gorm:"serializer:json;type:jsonb"`
+
+	// Reply-To address
+	ReplyTo string `json:"reply_to" gorm:"type:varchar(255)"`
+
+	// Subject line
+	// required: true
+	Subject string `json:"subject" gorm:"type:varchar(255)"`
+
+	// HTML body. Bodies may hold password reset and verification links, so they are never exposed
+	// and are cleared once the email is sent.
+	HTML string `json:"-" gorm:"type:text"`
+
+	// Plain text body
+	Text string `json:"-" gorm:"type:text"`
+
+	// Extra header fields
+	Headers map[string]string `json:"headers" gorm:"serializer:json;type:jsonb"`
+
+	// Delivery status (pending, sending, sent or dead)
+	// required: true
+	Status string `json:"status" gorm:"type:varchar(20);default:pending;index:idx_outbox_email_due,priority:1"`
+
+	// Number of delivery attempts made
+	// required: true
+	Attempts int `json:"attempts" gorm:"default:0"`
+
+	// Time of the next delivery attempt
+	// required: true
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index:idx_outbox_email_due,priority:2"`
+
+	// Error of the last failed attempt
+	LastError string `json:"last_error" gorm:"type:text"`
+
+	// ID assigned to the message by the mail transport
+	ProviderID string `json:"provider_id" gorm:"type:varchar(255)"`
+
+	// Delivery timestamp
+	SentAt *time.Time `json:"sent_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP;index"`
+
+	// Last update timestamp
+	// required: true
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -10,15 +10,15 @@ type Reaction struct {
 
 	// ID of the user who reacted to the news
 	// required: true
-	UserID uint64 `gorm:"index"`
+	UserID uint64 `gorm:"index;uniqueIndex:idx_reaction_user_news_type"`
 
 	// ID of the news that was reacted to
 	// required: true
-	NewsID uint64 `gorm:"index"`
+	NewsID uint64 `gorm:"index;uniqueIndex:idx_reaction_user_news_type"`
 
-	// Type of reaction (e.g., "like" or "dislike")
+	// Key of the reaction type from the reaction catalog (e.g., "like" or "dislike")
 	// required: true
-	Type string `gorm:"type:varchar(10)"`
+	Type string `gorm:"type:varchar(32);uniqueIndex:idx_reaction_user_news_type"`
 
 	// Timestamp of when the reaction occurred
 	// required: true
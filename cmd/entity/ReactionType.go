@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// swagger:model ReactionType
+type ReactionType struct {
+	// Reaction type ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Identifier used in the reaction endpoints (e.g. "like")
+	// required: true
+	Key string `json:"key" gorm:"type:varchar(32);uniqueIndex"`
+
+	// Emoji shown for the reaction
+	// required: true
+	Emoji string `json:"emoji" gorm:"type:varchar(16)"`
+
+	// Human readable label
+	// required: true
+	Label string `json:"label" gorm:"type:varchar(50)"`
+
+	// Reactions sharing a group are mutually exclusive; empty means the reaction stands alone
+	ExclusiveGroup string `json:"exclusive_group" gorm:"type:varchar(32);index"`
+
+	// Position of the reaction in the catalog
+	SortOrder int `json:"sort_order" gorm:"default:0"`
+
+	// Inactive reactions keep their counts but cannot be added anymore. There is no column default:
+	// GORM would replace a false value with it on insert.
+	Active bool `json:"active"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
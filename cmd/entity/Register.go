@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 // swagger:model Register
 type Register struct {
 	// Register ID
@@ -22,6 +24,21 @@ type Register struct {
 	// required: true
 	Password string `gorm:"type:varchar(255)"`
 
+	// Preferred locale of the user, used for the emails about the request
+	Locale string `gorm:"type:varchar(10)"`
+
+	// Set until the email address is verified; requests are only shown to admins afterwards
+	AwaitingVerification bool `gorm:"default:false;index"`
+
+	// Time the latest verification email was queued
+	VerificationSentAt *time.Time
+
+	// Start of the hour the verification resends are counted in
+	VerificationResendWindow *time.Time
+
+	// Number of verification resends in the current hour
+	VerificationResends int `gorm:"default:0"`
+
 	// Status of registration approval
 	Accepted bool `gorm:"default:false"`
 }
@@ -25,4 +25,7 @@ type Server struct {
 	// Current status of the server
 	// required: true
 	Status string `gorm:"type:varchar(50)"`
+
+	// Image shown for the server in the launcher
+	ImageURL string `gorm:"type:varchar(512)"`
 }
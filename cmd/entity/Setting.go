@@ -4,9 +4,19 @@ package entity
 type Setting struct {
 	// Setting ID
 	// required: true
-	ID uint64 `gorm:"primaryKey;autoIncrement"`
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Unique key of the setting, e.g. news_published
+	// required: true
+	Key string `json:"key" gorm:"type:varchar(50);uniqueIndex"`
 
 	// Description of the setting
 	// required: true
-	Description string `gorm:"type:varchar(255)"`
+	Description string `json:"description" gorm:"type:varchar(255)"`
+
+	// Roles the setting is enabled for until a user changes it
+	DefaultRoles []string `json:"default_roles" gorm:"serializer:json;type:jsonb"`
+
+	// Position of the setting in listings
+	SortOrder int `json:"sort_order" gorm:"default:0"`
 }
@@ -0,0 +1,12 @@
+package entity
+
+// swagger:model Tag
+type Tag struct {
+	// Tag ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Normalized tag name
+	// required: true
+	Name string `json:"name" gorm:"type:varchar(50);unique"`
+}
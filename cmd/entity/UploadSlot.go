@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// swagger:model UploadSlot
+type UploadSlot struct {
+	// Upload slot ID
+	// required: true
+	ID uint64 `json:"id" gorm:"primaryKey;autoIncrement"`
+
+	// Storage key the client uploads the file to
+	// required: true
+	Key string `json:"key" gorm:"type:varchar(255);uniqueIndex"`
+
+	// ID of the user who requested the slot
+	// required: true
+	UploadedBy uint64 `json:"uploaded_by" gorm:"index"`
+
+	// Declared MIME type of the file
+	// required: true
+	ContentType string `json:"content_type" gorm:"type:varchar(100)"`
+
+	// Declared size of the file in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// Declared SHA-256 of the file
+	// required: true
+	Hash string `json:"hash" gorm:"type:varchar(64)"`
+
+	// Status of the upload (pending, completed or rejected)
+	// required: true
+	Status string `json:"status" gorm:"type:varchar(20);default:pending;index"`
+
+	// Reason the uploaded file was rejected
+	RejectReason string `json:"reject_reason,omitempty" gorm:"type:varchar(255)"`
+
+	// Media asset created once the upload was verified
+	MediaID *uint64 `json:"media_id"`
+
+	// Deadline to upload and confirm the file
+	// required: true
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Creation timestamp
+	// required: true
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Confirmation timestamp
+	CompletedAt *time.Time `json:"completed_at"`
+}
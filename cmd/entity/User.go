@@ -35,4 +35,10 @@ type User struct {
 
 	// Active status of the user
 	IsActive bool `json:"is_active" gorm:"default:true"`
+
+	// Profile picture URL
+	AvatarURL string `json:"avatar_url" gorm:"type:varchar(512)"`
+
+	// Preferred locale for content and emails (e.g. "es", "en")
+	Locale string `json:"locale" gorm:"type:varchar(10)"`
 }
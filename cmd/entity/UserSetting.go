@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 // swagger:model UserSetting
 type UserSetting struct {
 	// UserSetting ID
@@ -8,13 +10,16 @@ type UserSetting struct {
 
 	// User ID
 	// required: true
-	UserID uint64 `gorm:"index"`
+	UserID uint64 `gorm:"uniqueIndex:idx_user_setting,priority:1"`
 
 	// Setting ID
 	// required: true
-	SettingID uint64 `gorm:"index"`
+	SettingID uint64 `gorm:"uniqueIndex:idx_user_setting,priority:2;index"`
 
 	// Active status of the setting
 	// required: true
 	IsActive bool `gorm:"default:true"`
+
+	// Time the user last changed the setting
+	UpdatedAt time.Time
 }
@@ -0,0 +1,39 @@
+package enums
+
+// Actions recorded in the audit log for administrative and moderation changes.
+const (
+	AuditUserCreate      = "user.create"
+	AuditUserUpdate      = "user.update"
+	AuditUserDelete      = "user.delete"
+	AuditUserRoleChange  = "user.role_change"
+	AuditRegisterApprove = "register.approve"
+	AuditRegisterDeny    = "register.deny"
+	AuditNewsCreate      = "news.create"
+	AuditNewsUpdate      = "news.update"
+	AuditNewsRestore     = "news.restore"
+	AuditNewsDelete      = "news.delete"
+	AuditNewsPublish     = "news.publish"
+	AuditNewsArchive     = "news.archive"
+	AuditCommentHide     = "comment.hide"
+	AuditCommentUnhide   = "comment.unhide"
+	AuditCommentDelete   = "comment.delete"
+	AuditReportDismiss   = "report.dismiss"
+)
+
+// Kinds of records the audit log entries point at.
+const (
+	AuditTargetUser     = "user"
+	AuditTargetRegister = "register"
+	AuditTargetNews     = "news"
+	AuditTargetComment  = "comment"
+	AuditTargetReport   = "comment_report"
+)
+
+// IsValidAuditTarget reports whether targetType is one of the audit log target types.
+func IsValidAuditTarget(targetType string) bool {
+	switch targetType {
+	case AuditTargetUser, AuditTargetRegister, AuditTargetNews, AuditTargetComment, AuditTargetReport:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package enums
+
+const (
+	CommentReportOpen      = "open"
+	CommentReportResolved  = "resolved"
+	CommentReportDismissed = "dismissed"
+)
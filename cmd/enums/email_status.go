@@ -0,0 +1,17 @@
+package enums
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusDead    = "dead"
+)
+
+// IsValidEmailStatus reports whether status is one of the known outbox email states.
+func IsValidEmailStatus(status string) bool {
+	switch status {
+	case EmailStatusPending, EmailStatusSending, EmailStatusSent, EmailStatusDead:
+		return true
+	}
+	return false
+}
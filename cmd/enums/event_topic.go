@@ -0,0 +1,17 @@
+package enums
+
+// Topics of the real-time event stream.
+const (
+	TopicNewsPublished = "news-published"
+	TopicNotification  = "notification"
+	TopicModeration    = "moderation"
+)
+
+// IsValidEventTopic reports whether topic is one of the event stream topics.
+func IsValidEventTopic(topic string) bool {
+	switch topic {
+	case TopicNewsPublished, TopicNotification, TopicModeration:
+		return true
+	}
+	return false
+}
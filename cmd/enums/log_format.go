@@ -0,0 +1,17 @@
+package enums
+
+// Formats the log is exported and archived in.
+const (
+	LogFormatJSON  = "json"
+	LogFormatJSONL = "jsonl"
+	LogFormatCSV   = "csv"
+)
+
+// IsValidLogFormat reports whether format is one of the log export formats.
+func IsValidLogFormat(format string) bool {
+	switch format {
+	case LogFormatJSON, LogFormatJSONL, LogFormatCSV:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package enums
+
+// Problems reported by the verification of the audit log.
+const (
+	// LogEntryModified means the entry no longer matches its hash.
+	LogEntryModified = "modified"
+	// LogChainBroken means the entry does not follow the previous one: entries were removed,
+	// inserted or reordered before it.
+	LogChainBroken = "broken_chain"
+	// LogCheckpointMismatch means the entry pinned by a checkpoint has a different hash.
+	LogCheckpointMismatch = "checkpoint_mismatch"
+	// LogCheckpointEntryMissing means the entry pinned by a checkpoint no longer exists.
+	LogCheckpointEntryMissing = "missing_entry"
+	// LogCheckpointInvalid means a checkpoint cannot be read or its signature is wrong.
+	LogCheckpointInvalid = "invalid_checkpoint"
+	// LogArchiveInvalid means the record of an archive has a wrong signature, so the entries it
+	// claims to have removed are not accounted for.
+	LogArchiveInvalid = "invalid_archive"
+)
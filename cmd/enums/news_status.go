@@ -0,0 +1,17 @@
+package enums
+
+const (
+	NewsStatusDraft     = "draft"
+	NewsStatusScheduled = "scheduled"
+	NewsStatusPublished = "published"
+	NewsStatusArchived  = "archived"
+)
+
+// IsValidNewsStatus reports whether status is one of the known news lifecycle states.
+func IsValidNewsStatus(status string) bool {
+	switch status {
+	case NewsStatusDraft, NewsStatusScheduled, NewsStatusPublished, NewsStatusArchived:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package enums
+
+// Keys of the notification settings users can turn on and off.
+const (
+	SettingNewsPublished      = "news_published"
+	SettingRegistrationStatus = "registration_status"
+	SettingModerationActions  = "moderation_actions"
+	SettingSecurityAlerts     = "security_alerts"
+)
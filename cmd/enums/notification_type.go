@@ -0,0 +1,11 @@
+package enums
+
+const (
+	NotificationRegistrationApproved = "registration_approved"
+	NotificationNewsPublished        = "news_published"
+	NotificationPasswordChanged      = "password_changed"
+	NotificationCommentHidden        = "comment_hidden"
+	NotificationCommentRestored      = "comment_restored"
+	NotificationCommentDeleted       = "comment_deleted"
+	NotificationReportResolved       = "report_resolved"
+)
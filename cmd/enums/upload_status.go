@@ -0,0 +1,7 @@
+package enums
+
+const (
+	UploadStatusPending   = "pending"
+	UploadStatusCompleted = "completed"
+	UploadStatusRejected  = "rejected"
+)
@@ -0,0 +1,6 @@
+package enums
+
+const (
+	VerificationRegister    = "register"
+	VerificationEmailChange = "email_change"
+)
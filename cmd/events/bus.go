@@ -0,0 +1,163 @@
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind before it is dropped.
+// Dropped subscribers reconnect and resume from their last event.
+const subscriberBuffer = 64
+
+// Event is a message published on the bus.
+type Event struct {
+	// ID orders the events of the bus; clients resume after the last ID they received
+	ID uint64 `json:"id"`
+
+	Topic string      `json:"topic"`
+	Data  interface{} `json:"data"`
+	Time  time.Time   `json:"time"`
+
+	// UserID restricts the event to a single user; zero sends it to every subscriber of the topic
+	UserID uint64 `json:"-"`
+}
+
+// Publisher is the side of the bus services publish events with.
+type Publisher interface {
+	// Publish sends the event to every subscriber of the topic.
+	Publish(topic string, data interface{})
+
+	// PublishTo sends the event to the subscribers of the topic that are the given user.
+	PublishTo(userID uint64, topic string, data interface{})
+}
+
+// Subscription receives the events of its topics until it is closed. Events is closed when the
+// subscriber falls too far behind or the subscription is closed.
+type Subscription struct {
+	Events <-chan Event
+
+	events chan Event
+	topics []string
+	userID uint64
+	bus    *Bus
+	once   sync.Once
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+func (s *Subscription) matches(event Event) bool {
+	return slices.Contains(s.topics, event.Topic) && (event.UserID == 0 || event.UserID == s.userID)
+}
+
+// Bus is an in-process publish/subscribe bus. It keeps the latest events so that subscribers that
+// reconnect can receive what they missed.
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBus creates a bus that keeps the given number of events for resuming. Event IDs start from
+// the current time, so IDs from before a restart are recognized as too old to resume from.
+func NewBus(historySize int) *Bus {
+	return &Bus{
+		lastID:      uint64(time.Now().UnixMicro()),
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *Bus) Publish(topic string, data interface{}) {
+	b.publish(Event{Topic: topic, Data: data})
+}
+
+func (b *Bus) PublishTo(userID uint64, topic string, data interface{}) {
+	b.publish(Event{Topic: topic, Data: data, UserID: userID})
+}
+
+func (b *Bus) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	event.Time = time.Now()
+
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, event)
+	}
+
+	for subscription := range b.subscribers {
+		if !subscription.matches(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			b.remove(subscription)
+		}
+	}
+}
+
+// Subscribe starts receiving the events of the topics meant for everyone or for the user. When
+// lastEventID is set, the retained events published after it are returned to be sent first;
+// resumed is false when some of them are no longer retained, and the subscriber should reload
+// its state instead.
+func (b *Bus) Subscribe(topics []string, userID uint64, lastEventID uint64) (subscription *Subscription, missed []Event, resumed bool) {
+	events := make(chan Event, subscriberBuffer)
+	subscription = &Subscription{
+		Events: events,
+		events: events,
+		topics: topics,
+		userID: userID,
+		bus:    b,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	resumed = true
+	if lastEventID != 0 && lastEventID < b.lastID {
+		oldest := b.lastID + 1
+		if len(b.history) > 0 {
+			oldest = b.history[0].ID
+		}
+		resumed = lastEventID >= oldest-1
+		for _, event := range b.history {
+			if event.ID > lastEventID && subscription.matches(event) {
+				missed = append(missed, event)
+			}
+		}
+	} else if lastEventID > b.lastID {
+		resumed = false
+	}
+
+	b.subscribers[subscription] = struct{}{}
+	return subscription, missed, resumed
+}
+
+// Subscribers returns the number of open subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove closes the subscription. The bus lock must be held.
+func (b *Bus) remove(subscription *Subscription) {
+	subscription.once.Do(func() {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	})
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TicketStore hands out short-lived, single-use tickets to open event streams. Browsers cannot set
+// headers on EventSource and WebSocket connections, and a ticket in the query string does not leak
+// a reusable token into access logs the way a JWT would.
+type TicketStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	tickets map[string]ticket
+}
+
+type ticket struct {
+	userID    uint64
+	roles     []string
+	expiresAt time.Time
+}
+
+// NewTicketStore creates a store whose tickets expire after ttl.
+func NewTicketStore(ttl time.Duration) *TicketStore {
+	return &TicketStore{ttl: ttl, tickets: make(map[string]ticket)}
+}
+
+// Issue creates a ticket for the user and returns it with its expiration.
+func (s *TicketStore) Issue(userID uint64, roles []string) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	value := hex.EncodeToString(buf)
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, t := range s.tickets {
+		if !now.Before(t.expiresAt) {
+			delete(s.tickets, key)
+		}
+	}
+	s.tickets[value] = ticket{userID: userID, roles: roles, expiresAt: expiresAt}
+	return value, expiresAt, nil
+}
+
+// Redeem consumes the ticket and returns the user it was issued to. It reports false when the
+// ticket is unknown, expired or already used.
+func (s *TicketStore) Redeem(value string) (uint64, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tickets[value]
+	if !ok {
+		return 0, nil, false
+	}
+	delete(s.tickets, value)
+	if !time.Now().Before(t.expiresAt) {
+		return 0, nil, false
+	}
+	return t.userID, t.roles, true
+}
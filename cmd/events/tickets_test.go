@@ -0,0 +1,35 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketIsSingleUse(t *testing.T) {
+	store := NewTicketStore(time.Minute)
+	ticket, expiresAt, err := store.Issue(7, []string{"PLAYER"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	userID, roles, ok := store.Redeem(ticket)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), userID)
+	assert.Equal(t, []string{"PLAYER"}, roles)
+
+	_, _, ok = store.Redeem(ticket)
+	assert.False(t, ok)
+}
+
+func TestTicketExpires(t *testing.T) {
+	store := NewTicketStore(-time.Second)
+	ticket, _, err := store.Issue(7, nil)
+	require.NoError(t, err)
+
+	_, _, ok := store.Redeem(ticket)
+	assert.False(t, ok)
+	_, _, ok = store.Redeem("unknown")
+	assert.False(t, ok)
+}
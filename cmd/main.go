@@ -17,25 +17,30 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"log"
+	"net/http"
 	"os"
 	"time"
 	"venecraft-back/cmd/controller"
+	"venecraft-back/cmd/email"
 	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/metrics"
 	"venecraft-back/cmd/middlewares"
 	"venecraft-back/cmd/repository"
 	"venecraft-back/cmd/routes"
 	"venecraft-back/cmd/seeds"
 	"venecraft-back/cmd/service"
+	"venecraft-back/cmd/storage"
 	"venecraft-back/cmd/utils"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -58,22 +63,6 @@ func init() {
 			log.Println("No .env.production file found. Ensure you have set the environment variables.")
 		}
 	}
-
-	// Initialize AWS S3
-	awsRegion := os.Getenv("AWS_REGION")
-	bucketName := os.Getenv("S3_BUCKET_NAME")
-	if bucketName == "" || awsRegion == "" {
-		log.Fatal("AWS_REGION and S3_BUCKET_NAME environment variables are required")
-	}
-
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(awsRegion))
-	if err != nil {
-		log.Fatalf("Unable to load AWS SDK config, %v", err)
-	}
-
-	s3Client := s3.NewFromConfig(cfg)
-	utils.InitializeS3(s3Client, bucketName) // Pass s3Client and bucketName to utils
-	log.Println("S3 client initialized successfully")
 }
 
 func connectDatabase() {
@@ -95,53 +84,170 @@ func connectDatabase() {
 
 	fmt.Println("Database connection established successfully!")
 
+	// The reactions unique index cannot be created while duplicated rows exist.
+	if err := repository.NewReactionRepository(DB).RemoveDuplicateReactions(); err != nil {
+		log.Fatal("Failed to remove duplicated reactions: ", err)
+	}
+
 	err = DB.AutoMigrate(&entity.Register{}, &entity.User{}, &entity.Role{}, &entity.Permission{},
 		&entity.RolePermission{}, &entity.UserRole{}, &entity.Server{},
 		&entity.Player{}, &entity.Ban{}, &entity.Log{}, &entity.Setting{},
-		&entity.UserSetting{}, &entity.News{}, &entity.Reaction{})
+		&entity.UserSetting{}, &entity.NewsCategory{}, &entity.Tag{}, &entity.News{}, &entity.ReactionType{}, &entity.Reaction{},
+		&entity.Comment{}, &entity.CommentReport{}, &entity.NewsRevision{}, &entity.NewsTranslation{}, &entity.NewsView{}, &entity.MediaAsset{}, &entity.UploadSlot{}, &entity.OutboxEmail{}, &entity.EmailVerification{}, &entity.Notification{}, &entity.LogArchive{})
 	if err != nil {
 		log.Fatal("Failed to migrate the database: ", err)
 	}
 
+	// Entries written before the log was hash chained are sealed into the chain once.
+	sealed, err := repository.NewLogRepository(DB).SealLegacyLogs()
+	if err != nil {
+		log.Fatal("Failed to seal the audit log: ", err)
+	}
+	if sealed > 0 {
+		log.Printf("Sealed %d audit log entries into the hash chain", sealed)
+	}
+
+	if err := repository.NewNewsRepository(DB).EnsureSearchIndex(utils.GetEnv("NEWS_SEARCH_LANGUAGE", "spanish")); err != nil {
+		log.Printf("News full-text search index unavailable: %v", err)
+	}
+
 	seeds.SeedRoles(DB)
 	seeds.SeedUsers(DB)
+	seeds.SeedNewsCategories(DB)
+	seeds.SeedReactionTypes(DB)
+	seeds.SeedSettings(DB)
 
 	fmt.Println("Database migrated successfully!")
 }
 
 func main() {
+	if err := utils.RequireEnv("UNSUBSCRIBE_SECRET", "LOG_CHECKPOINT_SECRET"); err != nil {
+		log.Fatal(err)
+	}
 	connectDatabase()
 
+	fileStorage, err := storage.New(context.Background())
+	if err != nil {
+		log.Fatalf("Unable to initialize file storage: %v", err)
+	}
+	auditStorage, err := storage.NewAudit(context.Background())
+	if err != nil {
+		log.Fatalf("Unable to initialize audit storage: %v", err)
+	}
+
+	mailer, err := email.NewMailer()
+	if err != nil {
+		log.Fatalf("Unable to initialize mailer: %v", err)
+	}
+	emailTemplates, err := email.DefaultTemplates()
+	if err != nil {
+		log.Fatalf("Unable to load email templates: %v", err)
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(DB)
 	roleRepo := repository.NewRoleRepository(DB)
-	userRoleRepo := repository.NewUserRoleRepository(DB)
 	registerRepo := repository.NewRegisterRepository(DB)
 	newsRepo := repository.NewNewsRepository(DB)
+	newsCategoryRepo := repository.NewNewsCategoryRepository(DB)
 	logrepo := repository.NewLogRepository(DB)
 	reactionRepo := repository.NewReactionRepository(DB)
+	reactionTypeRepo := repository.NewReactionTypeRepository(DB)
+	commentRepo := repository.NewCommentRepository(DB)
+	newsRevisionRepo := repository.NewNewsRevisionRepository(DB)
+	newsTranslationRepo := repository.NewNewsTranslationRepository(DB)
+	newsAnalyticsRepo := repository.NewNewsAnalyticsRepository(DB)
+	mediaRepo := repository.NewMediaRepository(DB)
+	uploadRepo := repository.NewUploadRepository(DB)
+	outboxRepo := repository.NewOutboxRepository(DB)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(DB)
+	settingRepo := repository.NewSettingRepository(DB)
+	notificationRepo := repository.NewNotificationRepository(DB)
+
+	eventBus := events.NewBus(utils.GetEnvInt("EVENT_HISTORY_SIZE", 1000))
+	streamTickets := events.NewTicketStore(utils.GetEnvDuration("EVENT_TICKET_TTL", 30*time.Second))
 
 	// Initialize services
-	userService := service.NewUserService(userRepo, roleRepo)
+	logService := service.NewLogService(logrepo, reactionTypeRepo, auditStorage)
+	if len(os.Args) > 1 && os.Args[1] == "verify-audit-log" {
+		verifyAuditLog(logService)
+		return
+	}
+
+	settingService := service.NewSettingService(settingRepo, userRepo)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, settingService, eventBus)
+	userService := service.NewUserService(userRepo, roleRepo, emailVerificationRepo, settingService, notificationService, logService, emailTemplates)
 	authService := service.NewAuthService(userRepo)
-	registerService := service.NewRegisterService(registerRepo, userRepo, roleRepo, userRoleRepo)
-	newsService := service.NewNewsService(newsRepo, reactionRepo, logrepo)
+	registerService := service.NewRegisterService(registerRepo, userRepo, roleRepo, emailVerificationRepo, settingService, notificationService, logService, emailTemplates)
+	emailVerificationService := service.NewEmailVerificationService(emailVerificationRepo, registerService, userService)
+	reactionService := service.NewReactionService(reactionRepo, reactionTypeRepo, newsRepo, logrepo)
+	imageService := service.NewImageService(fileStorage)
+	mediaService := service.NewMediaService(mediaRepo, imageService, fileStorage, utils.GetEnvDuration("MEDIA_GC_GRACE_PERIOD", 7*24*time.Hour))
+	uploadService := service.NewUploadService(uploadRepo, mediaService, imageService, fileStorage)
+	newsService := service.NewNewsService(newsRepo, newsCategoryRepo, reactionService, commentRepo, newsRevisionRepo, newsTranslationRepo, newsAnalyticsRepo, imageService, logService, userRepo, outboxRepo, settingService, notificationService, eventBus, emailTemplates)
+	newsCategoryService := service.NewNewsCategoryService(newsCategoryRepo)
+	newsTranslationService := service.NewNewsTranslationService(newsRepo, newsTranslationRepo)
+	newsAnalyticsService := service.NewNewsAnalyticsService(newsAnalyticsRepo, newsRepo)
+	commentService := service.NewCommentService(commentRepo, newsRepo, logService, notificationService, eventBus)
+	feedService := service.NewFeedService(newsRepo)
 	statsService := service.NewServerStatsService(userRepo, logrepo)
+	emailOutboxService := service.NewEmailOutboxService(outboxRepo, mailer)
+	emailTemplateService := service.NewEmailTemplateService(emailTemplates)
+	eventStreamService := service.NewEventStreamService(eventBus, streamTickets)
+
+	// Start background jobs
+	newsScheduler := service.NewNewsScheduler(newsService, utils.GetEnvDuration("NEWS_SCHEDULER_INTERVAL", time.Minute))
+	newsScheduler.Start(context.Background())
+	mediaGarbageCollector := service.NewMediaGarbageCollector(mediaService, uploadService, utils.GetEnvDuration("MEDIA_GC_INTERVAL", 24*time.Hour))
+	mediaGarbageCollector.Start(context.Background())
+	emailDispatcher := service.NewEmailDispatcher(emailOutboxService, utils.GetEnvDuration("EMAIL_DISPATCH_INTERVAL", 10*time.Second))
+	emailDispatcher.Start(context.Background())
+	logCheckpointer := service.NewLogCheckpointer(logService, utils.GetEnvDuration("LOG_CHECKPOINT_INTERVAL", time.Hour))
+	logCheckpointer.Start(context.Background())
+	logArchiver := service.NewLogArchiver(logService, utils.GetEnvDuration("LOG_ARCHIVE_INTERVAL", 24*time.Hour))
+	logArchiver.Start(context.Background())
+	metricsService := service.NewMetricsService(userRepo, registerRepo, outboxRepo, eventBus)
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		log.Fatal("Failed to access the database pool: ", err)
+	}
+	metricsRegistry := metrics.NewRegistry()
+	metricsRegistry.MustRegister(collectors.NewDBStatsCollector(sqlDB, os.Getenv("DB_NAME")))
+	metricsRegistry.MustRegister(metrics.NewAppCollector(metricsService))
+	httpMetrics := metrics.NewHTTPMetrics(metricsRegistry)
 
 	// Initialize controllers
 	userController := controller.NewUserController(userService)
 	authController := controller.NewAuthController(authService)
 	registerController := controller.NewRegisterController(registerService)
-	newsController := controller.NewNewsController(newsService)
+	emailVerificationController := controller.NewEmailVerificationController(emailVerificationService)
+	newsController := controller.NewNewsController(newsService, mediaService)
+	newsCategoryController := controller.NewNewsCategoryController(newsCategoryService)
+	newsTranslationController := controller.NewNewsTranslationController(newsTranslationService)
+	newsAnalyticsController := controller.NewNewsAnalyticsController(newsAnalyticsService)
+	commentController := controller.NewCommentController(commentService)
+	reactionController := controller.NewReactionController(reactionService)
+	mediaController := controller.NewMediaController(mediaService)
+	uploadController := controller.NewUploadController(uploadService)
+	feedController := controller.NewFeedController(feedService)
 	statsController := controller.NewServerStatsController(statsService)
+	emailOutboxController := controller.NewEmailOutboxController(emailOutboxService)
+	emailTemplateController := controller.NewEmailTemplateController(emailTemplateService)
+	settingController := controller.NewSettingController(settingService)
+	notificationController := controller.NewNotificationController(notificationService)
+	eventController := controller.NewEventController(eventStreamService)
+	logController := controller.NewLogController(logService)
 
 	server := gin.Default()
+	server.Use(middlewares.RequestIDMiddleware())
+	server.Use(middlewares.MetricsMiddleware(httpMetrics))
 
 	server.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "Accept-Language", middlewares.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "Last-Modified", middlewares.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -150,6 +256,21 @@ func main() {
 	server.POST("/api/reset-password", userController.ResetPassword)
 	routes.AuthRoutes(server, authController)
 	routes.RegisterRoutes(server, registerController)
+	routes.EmailVerificationRoutes(server, emailVerificationController)
+	routes.UnsubscribeRoutes(server, settingController)
+	routes.EventRoutes(server, eventController, streamTickets)
+	routes.FeedRoutes(server, feedController)
+
+	// The local storage serves its files itself; S3 objects are served by the bucket
+	for _, store := range []storage.Storage{fileStorage, auditStorage} {
+		if localStorage, ok := store.(*storage.LocalStorage); ok {
+			mountPath := localStorage.MountPath()
+			filesHandler := gin.WrapH(http.StripPrefix(mountPath, localStorage.Handler()))
+			server.GET(mountPath+"/*key", filesHandler)
+			server.HEAD(mountPath+"/*key", filesHandler)
+			server.PUT(mountPath+"/*key", filesHandler)
+		}
+	}
 
 	protected := server.Group("/api")
 	protected.Use(middlewares.AuthMiddleware())
@@ -159,7 +280,19 @@ func main() {
 		protected.GET("/register", registerController.GetAllRegisters)
 		routes.UserRoutes(protected, userController)
 		routes.NewsRoutes(protected, newsController)
+		routes.NewsCategoryRoutes(protected, newsCategoryController)
+		routes.NewsTranslationRoutes(protected, newsTranslationController)
+		routes.NewsAnalyticsRoutes(protected, newsAnalyticsController)
+		routes.CommentRoutes(protected, commentController)
+		routes.ReactionRoutes(protected, reactionController)
+		routes.MediaRoutes(protected, mediaController)
+		routes.UploadRoutes(protected, uploadController)
 		routes.ServerStatsRoutes(protected, statsController)
+		routes.EmailOutboxRoutes(protected, emailOutboxController)
+		routes.EmailTemplateRoutes(protected, emailTemplateController)
+		routes.SettingRoutes(protected, settingController)
+		routes.NotificationRoutes(protected, notificationController)
+		routes.LogRoutes(protected, logController)
 	}
 
 	// Health check route
@@ -181,12 +314,55 @@ func main() {
 	// Serve the Swagger spec
 	server.StaticFile("/swagger.yaml", "./swagger.yaml")
 
+	serveMetrics(server, metrics.Handler(metricsRegistry))
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
-	err := server.Run(":" + port)
+	err = server.Run(":" + port)
 	if err != nil {
 		return
 	}
 }
+
+// verifyAuditLog verifies the audit log from the command line, e.g. "venecraft-back verify-audit-log",
+// printing the result as JSON. It exits with status 1 when the log was tampered with.
+func verifyAuditLog(logService service.LogService) {
+	verification, err := logService.VerifyLogs(context.Background())
+	if err != nil {
+		log.Fatalf("Unable to verify the audit log: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(verification); err != nil {
+		log.Fatalf("Unable to print the verification: %v", err)
+	}
+	if !verification.Valid {
+		os.Exit(1)
+	}
+}
+
+// serveMetrics exposes the Prometheus metrics on their own listener when METRICS_ADDR is set, and
+// otherwise on the main server behind METRICS_TOKEN. Without either they are not served at all.
+func serveMetrics(server *gin.Engine, handler http.Handler) {
+	addr := os.Getenv("METRICS_ADDR")
+	token := os.Getenv("METRICS_TOKEN")
+
+	switch {
+	case addr != "":
+		metricsServer := gin.New()
+		metricsServer.Use(gin.Recovery())
+		routes.MetricsRoutes(metricsServer, handler, token)
+		go func() {
+			if err := metricsServer.Run(addr); err != nil {
+				log.Printf("Metrics server stopped: %v", err)
+			}
+		}()
+	case token != "":
+		routes.MetricsRoutes(server, handler, token)
+	default:
+		log.Println("Metrics disabled: set METRICS_ADDR or METRICS_TOKEN to expose /metrics")
+	}
+}
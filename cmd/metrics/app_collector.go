@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AppStats are the application gauges, read from the database on every scrape.
+type AppStats struct {
+	// OutboxEmails counts the outbox emails by delivery status.
+	OutboxEmails map[string]int64
+
+	ActiveUsers int64
+
+	// RegistrationsPendingApproval are verified requests waiting for an admin.
+	RegistrationsPendingApproval int64
+
+	// RegistrationsPendingVerification are requests whose email address is not verified yet.
+	RegistrationsPendingVerification int64
+
+	EventSubscribers int64
+}
+
+// StatsSource provides the application gauges.
+type StatsSource interface {
+	AppStats() (*AppStats, error)
+}
+
+// AppCollector exports the application gauges of a StatsSource.
+type AppCollector struct {
+	source StatsSource
+
+	outboxEmails     *prometheus.Desc
+	activeUsers      *prometheus.Desc
+	registrations    *prometheus.Desc
+	eventSubscribers *prometheus.Desc
+}
+
+func NewAppCollector(source StatsSource) *AppCollector {
+	return &AppCollector{
+		source: source,
+		outboxEmails: prometheus.NewDesc(prometheus.BuildFQName(namespace, "email_outbox", "emails"),
+			"Emails in the outbox, by delivery status.", []string{"status"}, nil),
+		activeUsers: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_users"),
+			"User accounts that are active.", nil, nil),
+		registrations: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "registrations_pending"),
+			"Registration requests waiting, by stage (verification or approval).", []string{"stage"}, nil),
+		eventSubscribers: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "event_stream_subscribers"),
+			"Clients connected to the real-time event stream.", nil, nil),
+	}
+}
+
+func (c *AppCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.outboxEmails
+	ch <- c.activeUsers
+	ch <- c.registrations
+	ch <- c.eventSubscribers
+}
+
+func (c *AppCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.source.AppStats()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.activeUsers, err)
+		return
+	}
+
+	for status, count := range stats.OutboxEmails {
+		ch <- prometheus.MustNewConstMetric(c.outboxEmails, prometheus.GaugeValue, float64(count), status)
+	}
+	ch <- prometheus.MustNewConstMetric(c.activeUsers, prometheus.GaugeValue, float64(stats.ActiveUsers))
+	ch <- prometheus.MustNewConstMetric(c.registrations, prometheus.GaugeValue, float64(stats.RegistrationsPendingApproval), "approval")
+	ch <- prometheus.MustNewConstMetric(c.registrations, prometheus.GaugeValue, float64(stats.RegistrationsPendingVerification), "verification")
+	ch <- prometheus.MustNewConstMetric(c.eventSubscribers, prometheus.GaugeValue, float64(stats.EventSubscribers))
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"venecraft-back/cmd/events"
 	"venecraft-back/cmd/utils"
 )
 
@@ -17,21 +18,52 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		authenticate(c, strings.TrimPrefix(authHeader, "Bearer "))
+	}
+}
 
-		claims, err := utils.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+// StreamAuthMiddleware authenticates event streams. Browsers cannot set headers on EventSource and
+// WebSocket connections, so a single-use ticket from the store may be given in the ticket query
+// parameter instead of the Authorization header.
+func StreamAuthMiddleware(tickets *events.TicketStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			authenticate(c, strings.TrimPrefix(authHeader, "Bearer "))
+			return
+		}
+
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header or ticket required"})
+			c.Abort()
+			return
+		}
+		userID, roles, ok := tickets.Redeem(ticket)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired ticket"})
 			c.Abort()
 			return
 		}
 
-		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
+		c.Set("userID", userID)
+		c.Set("role", roles)
 		c.Next()
 	}
 }
 
+func authenticate(c *gin.Context, tokenString string) {
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", claims.UserID)
+	c.Set("role", claims.Role)
+	c.Next()
+}
+
 func GetLoggedInUser(c *gin.Context) (uint64, []string, bool) {
 	userID, exists := c.Get("userID")
 	if !exists {
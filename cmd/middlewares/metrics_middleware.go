@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+	"venecraft-back/cmd/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records the count and latency of every request by route and status.
+func MetricsMiddleware(httpMetrics *metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpMetrics.Observe(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// MetricsTokenMiddleware only lets scrapers presenting the token as a bearer token through.
+func MetricsTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid metrics token"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
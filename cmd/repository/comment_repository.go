@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+
+	"gorm.io/gorm"
+)
+
+// CommentWithAuthor is a comment joined with the nickname of its author.
+type CommentWithAuthor struct {
+	entity.Comment
+	AuthorNickname string
+}
+
+type CommentRepository interface {
+	CreateComment(comment *entity.Comment) error
+	GetCommentByID(id uint64) (*entity.Comment, error)
+	GetCommentsByNews(newsID uint64) ([]CommentWithAuthor, error)
+	UpdateComment(comment *entity.Comment) error
+	CountVisibleCommentsByNews(newsIDs []uint64) (map[uint64]int64, error)
+	CountUserCommentsSince(userID uint64, since time.Time) (int64, error)
+
+	CreateReport(report *entity.CommentReport) error
+	HasUserReported(userID, commentID uint64) (bool, error)
+	GetReportByID(id uint64) (*entity.CommentReport, error)
+	GetReportsByStatus(status string) ([]entity.CommentReport, error)
+	UpdateReport(report *entity.CommentReport) error
+	ResolveOpenReportsForComment(commentID, moderatorID uint64, status string) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db}
+}
+
+func (r *commentRepository) CreateComment(comment *entity.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *commentRepository) GetCommentByID(id uint64) (*entity.Comment, error) {
+	var comment entity.Comment
+	if err := r.db.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetCommentsByNews returns every comment of a news, oldest first, so threads can be rebuilt in order.
+func (r *commentRepository) GetCommentsByNews(newsID uint64) ([]CommentWithAuthor, error) {
+	var comments []CommentWithAuthor
+	err := r.db.Model(&entity.Comment{}).
+		Select("comments.*, users.nickname AS author_nickname").
+		Joins("LEFT JOIN users ON users.id = comments.user_id").
+		Where("comments.news_id = ?", newsID).
+		Order("comments.created_at ASC, comments.id ASC").
+		Scan(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) UpdateComment(comment *entity.Comment) error {
+	return r.db.Save(comment).Error
+}
+
+// CountVisibleCommentsByNews counts the comments that are neither hidden nor deleted, by news.
+// News without such comments are left out.
+func (r *commentRepository) CountVisibleCommentsByNews(newsIDs []uint64) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64, len(newsIDs))
+	if len(newsIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		NewsID uint64
+		Count  int64
+	}
+	err := r.db.Model(&entity.Comment{}).
+		Select("news_id, COUNT(*) AS count").
+		Where("news_id IN ? AND hidden = ? AND deleted = ?", newsIDs, false, false).
+		Group("news_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.NewsID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *commentRepository) CountUserCommentsSince(userID uint64, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Comment{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *commentRepository) CreateReport(report *entity.CommentReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *commentRepository) HasUserReported(userID, commentID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.CommentReport{}).
+		Where("reporter_id = ? AND comment_id = ?", userID, commentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commentRepository) GetReportByID(id uint64) (*entity.CommentReport, error) {
+	var report entity.CommentReport
+	if err := r.db.First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *commentRepository) GetReportsByStatus(status string) ([]entity.CommentReport, error) {
+	var reports []entity.CommentReport
+	query := r.db.Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&reports).Error
+	return reports, err
+}
+
+func (r *commentRepository) UpdateReport(report *entity.CommentReport) error {
+	return r.db.Save(report).Error
+}
+
+// ResolveOpenReportsForComment closes every pending report of a comment once a moderator acted on it.
+func (r *commentRepository) ResolveOpenReportsForComment(commentID, moderatorID uint64, status string) error {
+	now := time.Now()
+	return r.db.Model(&entity.CommentReport{}).
+		Where("comment_id = ? AND status = ?", commentID, enums.CommentReportOpen).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": moderatorID,
+			"resolved_at": now,
+		}).Error
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerificationRepository interface {
+	CreateVerification(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error
+	GetVerificationByTokenHash(tokenHash string) (*entity.EmailVerification, error)
+	GetPendingVerificationByEmail(email string, now time.Time) (*entity.EmailVerification, error)
+	IncrementAttempts(id uint64) error
+	CompleteRegisterVerification(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error
+	CompleteEmailChange(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error
+}
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db}
+}
+
+// createVerification replaces the pending verifications of the same subject, so only the latest
+// link and code sent remain valid. The wrong codes entered for the replaced verifications still
+// count, so asking for a new code does not allow more guesses.
+func createVerification(tx *gorm.DB, verification *entity.EmailVerification) error {
+	pending := tx.Model(&entity.EmailVerification{}).
+		Where("purpose = ? AND subject_id = ? AND consumed_at IS NULL", verification.Purpose, verification.SubjectID).
+		Session(&gorm.Session{})
+
+	var attempts int
+	if err := pending.Select("COALESCE(MAX(attempts), 0)").Scan(&attempts).Error; err != nil {
+		return err
+	}
+	if attempts > verification.Attempts {
+		verification.Attempts = attempts
+	}
+
+	if err := pending.Delete(&entity.EmailVerification{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(verification).Error
+}
+
+func (r *emailVerificationRepository) CreateVerification(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createVerification(tx, verification); err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
+}
+
+func (r *emailVerificationRepository) GetVerificationByTokenHash(tokenHash string) (*entity.EmailVerification, error) {
+	var verification entity.EmailVerification
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&verification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// GetPendingVerificationByEmail returns the latest verification of the address that can still be
+// completed.
+func (r *emailVerificationRepository) GetPendingVerificationByEmail(email string, now time.Time) (*entity.EmailVerification, error) {
+	var verification entity.EmailVerification
+	err := r.db.Where("email = ? AND consumed_at IS NULL AND expires_at > ?", email, now).
+		Order("created_at DESC, id DESC").
+		First(&verification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *emailVerificationRepository) IncrementAttempts(id uint64) error {
+	return r.db.Model(&entity.EmailVerification{}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// consume marks the verification as used. It fails when another request consumed it first.
+func consume(tx *gorm.DB, verification *entity.EmailVerification) error {
+	now := time.Now()
+	result := tx.Model(&entity.EmailVerification{}).
+		Where("id = ? AND consumed_at IS NULL", verification.ID).
+		Update("consumed_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	verification.ConsumedAt = &now
+	return nil
+}
+
+// CompleteRegisterVerification marks the registration request as verified, which puts it in the
+// admin queue, and queues the emails about it.
+func (r *emailVerificationRepository) CompleteRegisterVerification(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := consume(tx, verification); err != nil {
+			return err
+		}
+		err := tx.Model(&entity.Register{}).
+			Where("id = ?", verification.SubjectID).
+			Update("awaiting_verification", false).Error
+		if err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
+}
+
+// CompleteEmailChange replaces the user's email with the verified address and queues the emails
+// about it.
+func (r *emailVerificationRepository) CompleteEmailChange(verification *entity.EmailVerification, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := consume(tx, verification); err != nil {
+			return err
+		}
+		err := tx.Model(&entity.User{}).
+			Where("id = ?", verification.SubjectID).
+			Update("email", verification.Email).Error
+		if err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
+}
+
+// verificationExpired is the condition of registration requests whose verification can no longer
+// be completed.
+const verificationExpired = `NOT EXISTS (
+	SELECT 1 FROM email_verifications v
+	WHERE v.purpose = ? AND v.subject_id = registers.id AND v.expires_at > ?
+)`
+
+func deleteUnverifiedRegisters(tx *gorm.DB, now time.Time) error {
+	return tx.Where("awaiting_verification = ? AND "+verificationExpired, true, enums.VerificationRegister, now).
+		Delete(&entity.Register{}).Error
+}
@@ -1,17 +1,51 @@
 package repository
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 	"venecraft-back/cmd/entity"
 )
 
+// logChainLockID is the PostgreSQL advisory lock serializing the writes to the log hash chain.
+const logChainLockID = 7_412_305
+
+// logSealBatchSize is how many legacy log entries are hashed per query.
+const logSealBatchSize = 1000
+
+// LogFilter holds the optional filters of the log listing.
+type LogFilter struct {
+	UserID     uint64
+	Action     string
+	TargetType string
+	TargetID   uint64
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// LogRetention is how long log entries are kept, by action. Entries of the other actions are kept
+// for Default; zero keeps them forever.
+type LogRetention struct {
+	Actions map[string]time.Duration
+	Default time.Duration
+}
+
 type LogRepository interface {
 	CreateLog(log *entity.Log) error
 	GetLogByID(id uint64) (*entity.Log, error)
-	GetAllLogs() ([]entity.Log, error)
-	UpdateLog(log *entity.Log) error
-	DeleteLog(id uint64) error
+	GetLogPage(filter LogFilter, limit, offset int) ([]entity.Log, int64, error)
+	GetLastLog() (*entity.Log, error)
+	GetLogsAfter(afterID uint64, limit int) ([]entity.Log, error)
+	SealLegacyLogs() (int64, error)
+	EachLog(filter LogFilter, batchSize int, fn func([]entity.Log) error) error
+	GetExpiredLogs(retention LogRetention, now time.Time, limit int) ([]entity.Log, error)
+	ArchiveLogs(archive *entity.LogArchive, ids []uint64) error
+	GetLogArchives() ([]entity.LogArchive, error)
 	CountTransactions(action string, fromDate time.Time) (int, error)
 }
 
@@ -23,31 +57,271 @@ func NewLogRepository(db *gorm.DB) LogRepository {
 	return &logRepository{db}
 }
 
+// CreateLog appends the entry to the log, chaining it to the hash of the previous entry. Entries
+// are never updated or deleted afterwards.
 func (r *logRepository) CreateLog(log *entity.Log) error {
-	return r.db.Create(log).Error
+	if log.Timestamp.IsZero() {
+		log.Timestamp = time.Now()
+	}
+	// PostgreSQL stores microseconds; the hash must match the stored value.
+	log.Timestamp = log.Timestamp.UTC().Truncate(time.Microsecond)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", logChainLockID).Error; err != nil {
+			return err
+		}
+		previous, err := lastLog(tx)
+		if err != nil {
+			return err
+		}
+
+		log.PrevHash = ""
+		if previous != nil {
+			log.PrevHash = previous.Hash
+		}
+		if log.Hash, err = LogChainHash(log); err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
 }
 
 func (r *logRepository) GetLogByID(id uint64) (*entity.Log, error) {
 	var log entity.Log
 	err := r.db.First(&log, id).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &log, nil
 }
 
-func (r *logRepository) GetAllLogs() ([]entity.Log, error) {
+// GetLogPage returns a page of the logs matching the filter, newest first, with the total count.
+func (r *logRepository) GetLogPage(filter LogFilter, limit, offset int) ([]entity.Log, int64, error) {
+	query := filterLogs(r.db.Model(&entity.Log{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []entity.Log
+	err := query.Order("timestamp DESC, id DESC").Limit(limit).Offset(offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// EachLog calls fn with the logs matching the filter in chain order, batchSize at a time, so the
+// whole log never has to be loaded at once.
+func (r *logRepository) EachLog(filter LogFilter, batchSize int, fn func([]entity.Log) error) error {
+	var afterID uint64
+	for {
+		var logs []entity.Log
+		err := filterLogs(r.db.Model(&entity.Log{}), filter).
+			Where("id > ?", afterID).Order("id").Limit(batchSize).Find(&logs).Error
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+		if len(logs) < batchSize {
+			return nil
+		}
+		afterID = logs[len(logs)-1].ID
+	}
+}
+
+// GetExpiredLogs returns the oldest entries past their retention, in chain order. The latest entry
+// is never returned, as the next entry is chained to it.
+func (r *logRepository) GetExpiredLogs(retention LogRetention, now time.Time, limit int) ([]entity.Log, error) {
+	var conditions []string
+	var args []interface{}
+	actions := make([]string, 0, len(retention.Actions))
+	for action, keep := range retention.Actions {
+		actions = append(actions, action)
+		if keep > 0 {
+			conditions = append(conditions, "(action = ? AND timestamp < ?)")
+			args = append(args, action, now.Add(-keep))
+		}
+	}
+	if retention.Default > 0 {
+		if len(actions) > 0 {
+			conditions = append(conditions, "(action NOT IN ? AND timestamp < ?)")
+			args = append(args, actions, now.Add(-retention.Default))
+		} else {
+			conditions = append(conditions, "timestamp < ?")
+			args = append(args, now.Add(-retention.Default))
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var logs []entity.Log
+	err := r.db.Where(strings.Join(conditions, " OR "), args...).
+		Where("id < (SELECT MAX(id) FROM logs)").
+		Order("id").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// ArchiveLogs records the archive and deletes the archived entries in the same transaction.
+func (r *logRepository) ArchiveLogs(archive *entity.LogArchive, ids []uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(archive).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&entity.Log{}).Error
+	})
+}
+
+func (r *logRepository) GetLogArchives() ([]entity.LogArchive, error) {
+	var archives []entity.LogArchive
+	err := r.db.Order("first_log_id").Find(&archives).Error
+	return archives, err
+}
+
+func filterLogs(query *gorm.DB, filter LogFilter) *gorm.DB {
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("timestamp < ?", *filter.To)
+	}
+	return query
+}
+
+func (r *logRepository) GetLastLog() (*entity.Log, error) {
+	return lastLog(r.db)
+}
+
+// GetLogsAfter returns the entries following afterID in chain order.
+func (r *logRepository) GetLogsAfter(afterID uint64, limit int) ([]entity.Log, error) {
 	var logs []entity.Log
-	err := r.db.Find(&logs).Error
+	err := r.db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&logs).Error
 	return logs, err
 }
 
-func (r *logRepository) UpdateLog(log *entity.Log) error {
-	return r.db.Save(log).Error
+// SealLegacyLogs hashes the entries written before the log was chained. It does nothing once the
+// chain has started, so entries stripped of their hash later are reported by the verification
+// instead of being sealed again.
+func (r *logRepository) SealLegacyLogs() (int64, error) {
+	var sealed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", logChainLockID).Error; err != nil {
+			return err
+		}
+		var chained []uint64
+		if err := tx.Model(&entity.Log{}).Where("hash <> ''").Limit(1).Pluck("id", &chained).Error; err != nil {
+			return err
+		}
+		if len(chained) > 0 {
+			return nil
+		}
+
+		prevHash := ""
+		var afterID uint64
+		for {
+			var logs []entity.Log
+			if err := tx.Where("id > ?", afterID).Order("id").Limit(logSealBatchSize).Find(&logs).Error; err != nil {
+				return err
+			}
+			for i := range logs {
+				entry := &logs[i]
+				entry.Timestamp = entry.Timestamp.UTC()
+				entry.PrevHash = prevHash
+				hash, err := LogChainHash(entry)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(entry).UpdateColumns(map[string]interface{}{"prev_hash": prevHash, "hash": hash}).Error; err != nil {
+					return err
+				}
+				prevHash = hash
+				afterID = entry.ID
+				sealed++
+			}
+			if len(logs) < logSealBatchSize {
+				return nil
+			}
+		}
+	})
+	return sealed, err
+}
+
+// LogChainHash returns the SHA-256 of the entry and the hash of the entry before it, hex encoded.
+// JSON snapshots are hashed in canonical form, as PostgreSQL does not keep the original text of
+// jsonb values.
+func LogChainHash(log *entity.Log) (string, error) {
+	before, err := canonicalJSON(log.Before)
+	if err != nil {
+		return "", err
+	}
+	after, err := canonicalJSON(log.After)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := json.Marshal([]interface{}{
+		log.PrevHash,
+		log.UserID,
+		log.Action,
+		log.Description,
+		log.TargetType,
+		log.TargetID,
+		before,
+		after,
+		log.IP,
+		log.UserAgent,
+		log.RequestID,
+		log.Timestamp.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
 }
 
-func (r *logRepository) DeleteLog(id uint64) error {
-	return r.db.Delete(&entity.Log{}, id).Error
+func lastLog(db *gorm.DB) (*entity.Log, error) {
+	var log entity.Log
+	if err := db.Order("id DESC").Take(&log).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &log, nil
 }
 
 // CountTransactions counts logs where the action is "transaction" and timestamp is within a specified range
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"venecraft-back/cmd/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogEntry() *entity.Log {
+	return &entity.Log{
+		ID:          12,
+		PrevHash:    "previous",
+		UserID:      3,
+		Action:      "news_update",
+		Description: "User with id: 3 performed news_update on news with id: 9",
+		TargetType:  "news",
+		TargetID:    9,
+		Before:      json.RawMessage(`{"title":"Old","tags":["a","b"]}`),
+		After:       json.RawMessage(`{"title":"New","tags":["a","b"]}`),
+		IP:          "203.0.113.7",
+		UserAgent:   "launcher/1.0",
+		RequestID:   "req-1",
+		Timestamp:   time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC),
+	}
+}
+
+func TestLogChainHashIsStable(t *testing.T) {
+	entry := testLogEntry()
+	hash, err := LogChainHash(entry)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// jsonb reorders keys and drops the whitespace, and timestamps come back in the local zone.
+	stored := testLogEntry()
+	stored.Before = json.RawMessage(`{ "tags": ["a", "b"],  "title": "Old" }`)
+	stored.Timestamp = entry.Timestamp.In(time.FixedZone("CET", 3600))
+	stored.Hash = hash
+	storedHash, err := LogChainHash(stored)
+	require.NoError(t, err)
+	assert.Equal(t, hash, storedHash)
+}
+
+func TestLogChainHashCoversEveryField(t *testing.T) {
+	hash, err := LogChainHash(testLogEntry())
+	require.NoError(t, err)
+
+	changes := map[string]func(*entity.Log){
+		"prev hash":   func(l *entity.Log) { l.PrevHash = "other" },
+		"user":        func(l *entity.Log) { l.UserID = 4 },
+		"action":      func(l *entity.Log) { l.Action = "news_delete" },
+		"description": func(l *entity.Log) { l.Description = "edited" },
+		"target type": func(l *entity.Log) { l.TargetType = "user" },
+		"target id":   func(l *entity.Log) { l.TargetID = 10 },
+		"before":      func(l *entity.Log) { l.Before = json.RawMessage(`{"title":"Forged"}`) },
+		"after":       func(l *entity.Log) { l.After = nil },
+		"ip":          func(l *entity.Log) { l.IP = "198.51.100.1" },
+		"user agent":  func(l *entity.Log) { l.UserAgent = "curl" },
+		"request id":  func(l *entity.Log) { l.RequestID = "req-2" },
+		"timestamp":   func(l *entity.Log) { l.Timestamp = l.Timestamp.Add(time.Microsecond) },
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			entry := testLogEntry()
+			change(entry)
+			changed, err := LogChainHash(entry)
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, changed)
+		})
+	}
+}
+
+func TestLogChainHashRejectsInvalidSnapshot(t *testing.T) {
+	entry := testLogEntry()
+	entry.After = json.RawMessage(`{"title":`)
+	_, err := LogChainHash(entry)
+	assert.Error(t, err)
+}
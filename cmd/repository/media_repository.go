@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+)
+
+// MediaReference is a row that points to a media asset by its URL.
+type MediaReference struct {
+	Type string `json:"type"`
+	ID   uint64 `json:"id"`
+}
+
+// MediaFilter holds the optional filters of the media library listing.
+type MediaFilter struct {
+	UploadedBy  uint64
+	ContentType string
+}
+
+// mediaReferencesQuery lists the rows using the URL given by the SQL expression: news, news
+// revisions, servers and user profiles showing it as their image, and news, translations and
+// revisions embedding it in their Markdown content. Image variants share the URL of their original
+// up to the extension, so content embedding a variant references the original too.
+func mediaReferencesQuery(url string) string {
+	stem := `regexp_replace(` + url + `, '\.[^./]*$', '')`
+	return `
+	SELECT 'news' AS type, id FROM news WHERE image_url = ` + url + `
+	UNION ALL SELECT 'news_revision', id FROM news_revisions WHERE image_url = ` + url + `
+	UNION ALL SELECT 'server', id FROM servers WHERE image_url = ` + url + `
+	UNION ALL SELECT 'user', id FROM users WHERE avatar_url = ` + url + `
+	UNION ALL SELECT 'news_content', id FROM news WHERE strpos(content, ` + stem + `) > 0
+	UNION ALL SELECT 'news_translation', id FROM news_translations WHERE strpos(content, ` + stem + `) > 0
+	UNION ALL SELECT 'news_revision_content', id FROM news_revisions WHERE strpos(content, ` + stem + `) > 0`
+}
+
+type MediaRepository interface {
+	CreateMedia(media *entity.MediaAsset) error
+	GetMediaByID(id uint64) (*entity.MediaAsset, error)
+	GetMediaByKey(key string) (*entity.MediaAsset, error)
+	GetExistingKeys(keys []string) (map[string]bool, error)
+	GetMediaPage(filter MediaFilter, limit, offset int) ([]entity.MediaAsset, int64, error)
+	DeleteMedia(id uint64) error
+	GetReferences(url string) ([]MediaReference, error)
+	CountReferences(urls []string) (map[string]int64, error)
+	GetUnreferencedMedia(createdBefore time.Time, limit int) ([]entity.MediaAsset, error)
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db}
+}
+
+func (r *mediaRepository) CreateMedia(media *entity.MediaAsset) error {
+	return r.db.Create(media).Error
+}
+
+func (r *mediaRepository) GetMediaByID(id uint64) (*entity.MediaAsset, error) {
+	var media entity.MediaAsset
+	if err := r.db.First(&media, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) GetMediaByKey(key string) (*entity.MediaAsset, error) {
+	var media entity.MediaAsset
+	if err := r.db.Where("key = ?", key).First(&media).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) GetExistingKeys(keys []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(keys))
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := r.db.Model(&entity.MediaAsset{}).Where("key IN ?", keys).Pluck("key", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, key := range found {
+		existing[key] = true
+	}
+	return existing, nil
+}
+
+func (r *mediaRepository) GetMediaPage(filter MediaFilter, limit, offset int) ([]entity.MediaAsset, int64, error) {
+	query := r.db.Model(&entity.MediaAsset{})
+	if filter.UploadedBy != 0 {
+		query = query.Where("uploaded_by = ?", filter.UploadedBy)
+	}
+	if filter.ContentType != "" {
+		query = query.Where("content_type LIKE ?", filter.ContentType+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var media []entity.MediaAsset
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&media).Error
+	return media, total, err
+}
+
+func (r *mediaRepository) DeleteMedia(id uint64) error {
+	return r.db.Delete(&entity.MediaAsset{}, id).Error
+}
+
+func (r *mediaRepository) GetReferences(url string) ([]MediaReference, error) {
+	var references []MediaReference
+	err := r.db.Raw("SELECT type, id FROM ("+mediaReferencesQuery("@url")+") refs ORDER BY type, id", sql.Named("url", url)).
+		Scan(&references).Error
+	return references, err
+}
+
+func (r *mediaRepository) CountReferences(urls []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(urls))
+	if len(urls) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		URL   string
+		Count int64
+	}
+	err := r.db.Raw("SELECT u.url, (SELECT COUNT(*) FROM ("+mediaReferencesQuery("u.url")+") refs) AS count "+
+		"FROM unnest(ARRAY[?]::text[]) AS u(url)", urls).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.URL] = row.Count
+	}
+	return counts, nil
+}
+
+// GetUnreferencedMedia returns media uploaded before the given moment that nothing points to.
+func (r *mediaRepository) GetUnreferencedMedia(createdBefore time.Time, limit int) ([]entity.MediaAsset, error) {
+	var media []entity.MediaAsset
+	err := r.db.
+		Where("created_at < ?", createdBefore).
+		Where("NOT EXISTS (" + mediaReferencesQuery("media_assets.url") + ")").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
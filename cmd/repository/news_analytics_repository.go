@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"time"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DailyCount is the number of events on one UTC day.
+type DailyCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// DailyTypeCount is the number of reactions of one type on one UTC day.
+type DailyTypeCount struct {
+	Day   time.Time
+	Type  string
+	Count int64
+}
+
+// NewsEngagementRow sums up the engagement of one news over a period.
+type NewsEngagementRow struct {
+	NewsID        uint64     `json:"news_id"`
+	Title         string     `json:"title"`
+	PublishAt     *time.Time `json:"publish_at"`
+	Views         int64      `json:"views"`
+	UniqueViewers int64      `json:"unique_viewers"`
+	Reactions     int64      `json:"reactions"`
+	Comments      int64      `json:"comments"`
+}
+
+// AnalyticsScope restricts the analytics queries to a period and, optionally, a single news.
+type AnalyticsScope struct {
+	NewsID *uint64
+	From   time.Time
+	To     time.Time
+}
+
+// days returns the period as UTC dates, as stored in news_views.view_date.
+func (s AnalyticsScope) days() (string, string) {
+	return s.From.UTC().Format(time.DateOnly), s.To.UTC().Format(time.DateOnly)
+}
+
+type NewsAnalyticsRepository interface {
+	RecordView(view *entity.NewsView) (bool, error)
+	CountViews(scope AnalyticsScope) (int64, int64, error)
+	GetViewTrend(scope AnalyticsScope) ([]DailyCount, error)
+	GetReactionTrend(scope AnalyticsScope) ([]DailyTypeCount, error)
+	GetCommentTrend(scope AnalyticsScope) ([]DailyCount, error)
+	GetNewsEngagement(scope AnalyticsScope, limit int) ([]NewsEngagementRow, error)
+}
+
+type newsAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsAnalyticsRepository(db *gorm.DB) NewsAnalyticsRepository {
+	return &newsAnalyticsRepository{db}
+}
+
+// RecordView stores the view unless the user already viewed the news that day. It reports
+// whether the view was new.
+func (r *newsAnalyticsRepository) RecordView(view *entity.NewsView) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(view)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountViews returns the number of daily views and of distinct viewers.
+func (r *newsAnalyticsRepository) CountViews(scope AnalyticsScope) (int64, int64, error) {
+	var totals struct {
+		Views         int64
+		UniqueViewers int64
+	}
+	err := r.scoped(scope, "news_views", "view_date").
+		Model(&entity.NewsView{}).
+		Select("COUNT(*) AS views, COUNT(DISTINCT user_id) AS unique_viewers").
+		Scan(&totals).Error
+	return totals.Views, totals.UniqueViewers, err
+}
+
+func (r *newsAnalyticsRepository) GetViewTrend(scope AnalyticsScope) ([]DailyCount, error) {
+	var trend []DailyCount
+	err := r.scoped(scope, "news_views", "view_date").
+		Model(&entity.NewsView{}).
+		Select("view_date AS day, COUNT(*) AS count").
+		Group("view_date").
+		Order("view_date ASC").
+		Scan(&trend).Error
+	return trend, err
+}
+
+func (r *newsAnalyticsRepository) GetReactionTrend(scope AnalyticsScope) ([]DailyTypeCount, error) {
+	var trend []DailyTypeCount
+	err := r.scoped(scope, "reactions", "timestamp").
+		Model(&entity.Reaction{}).
+		Select("(reactions.timestamp AT TIME ZONE 'UTC')::date AS day, type, COUNT(*) AS count").
+		Group("day, type").
+		Order("day ASC").
+		Scan(&trend).Error
+	return trend, err
+}
+
+func (r *newsAnalyticsRepository) GetCommentTrend(scope AnalyticsScope) ([]DailyCount, error) {
+	var trend []DailyCount
+	err := r.scoped(scope, "comments", "created_at").
+		Model(&entity.Comment{}).
+		Where("deleted = ?", false).
+		Select("(comments.created_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS count").
+		Group("day").
+		Order("day ASC").
+		Scan(&trend).Error
+	return trend, err
+}
+
+// GetNewsEngagement lists the engagement of every news over the period, most viewed first.
+func (r *newsAnalyticsRepository) GetNewsEngagement(scope AnalyticsScope, limit int) ([]NewsEngagementRow, error) {
+	var rows []NewsEngagementRow
+	fromDay, toDay := scope.days()
+	query := r.db.Table("news").
+		Select(`news.id AS news_id, news.title, news.publish_at,
+			(SELECT COUNT(*) FROM news_views v WHERE v.news_id = news.id AND v.view_date BETWEEN ? AND ?) AS views,
+			(SELECT COUNT(DISTINCT v.user_id) FROM news_views v WHERE v.news_id = news.id AND v.view_date BETWEEN ? AND ?) AS unique_viewers,
+			(SELECT COUNT(*) FROM reactions r WHERE r.news_id = news.id AND r.timestamp BETWEEN ? AND ?) AS reactions,
+			(SELECT COUNT(*) FROM comments c WHERE c.news_id = news.id AND c.deleted = false AND c.created_at BETWEEN ? AND ?) AS comments`,
+			fromDay, toDay, fromDay, toDay, scope.From, scope.To, scope.From, scope.To).
+		Order("views DESC, news.id DESC").
+		Limit(limit)
+	if scope.NewsID != nil {
+		query = query.Where("news.id = ?", *scope.NewsID)
+	}
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+// scoped filters a table by the scope's period on the given column and by news when requested.
+func (r *newsAnalyticsRepository) scoped(scope AnalyticsScope, table, column string) *gorm.DB {
+	query := r.db
+	if column == "view_date" {
+		from, to := scope.days()
+		query = query.Where(table+"."+column+" BETWEEN ? AND ?", from, to)
+	} else {
+		query = query.Where(table+"."+column+" BETWEEN ? AND ?", scope.From, scope.To)
+	}
+	if scope.NewsID != nil {
+		query = query.Where(table+".news_id = ?", *scope.NewsID)
+	}
+	return query
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+)
+
+type NewsCategoryRepository interface {
+	CreateCategory(category *entity.NewsCategory) error
+	GetAllCategories() ([]entity.NewsCategory, error)
+	GetCategoryByID(id uint64) (*entity.NewsCategory, error)
+	GetCategoryBySlug(slug string) (*entity.NewsCategory, error)
+	UpdateCategory(category *entity.NewsCategory) error
+	DeleteCategory(id uint64) error
+}
+
+type newsCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsCategoryRepository(db *gorm.DB) NewsCategoryRepository {
+	return &newsCategoryRepository{db}
+}
+
+func (r *newsCategoryRepository) CreateCategory(category *entity.NewsCategory) error {
+	return r.db.Create(category).Error
+}
+
+func (r *newsCategoryRepository) GetAllCategories() ([]entity.NewsCategory, error) {
+	var categories []entity.NewsCategory
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *newsCategoryRepository) GetCategoryByID(id uint64) (*entity.NewsCategory, error) {
+	var category entity.NewsCategory
+	if err := r.db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *newsCategoryRepository) GetCategoryBySlug(slug string) (*entity.NewsCategory, error) {
+	var category entity.NewsCategory
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *newsCategoryRepository) UpdateCategory(category *entity.NewsCategory) error {
+	return r.db.Save(category).Error
+}
+
+// DeleteCategory removes the category and leaves its news uncategorized.
+func (r *newsCategoryRepository) DeleteCategory(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.News{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.NewsCategory{}, id).Error
+	})
+}
@@ -2,18 +2,49 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"time"
 
 	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
 
 	"gorm.io/gorm"
 )
 
+// NewsFilter narrows down the news returned by the list queries.
+type NewsFilter struct {
+	// IncludeUnpublished also returns drafts, scheduled, archived and expired news.
+	IncludeUnpublished bool
+
+	// CategorySlug keeps only news in the given category.
+	CategorySlug string
+
+	// Tag keeps only news tagged with the given name.
+	Tag string
+}
+
+// NewsSearchHit is a news matching a full-text search, with its rank and highlighted fragments.
+type NewsSearchHit struct {
+	entity.News
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
 type NewsRepository interface {
 	CreateNews(news *entity.News) error
-	GetNewsByID(id uint64) (*entity.News, error)
-	GetAllNews() ([]entity.News, error)
-	GetLatestNews() ([]entity.News, error)
+	GetNewsByID(id uint64, includeUnpublished bool) (*entity.News, error)
+	GetAllNews(filter NewsFilter) ([]entity.News, error)
+	GetLatestNews(filter NewsFilter) ([]entity.News, error)
+	GetOrCreateTags(names []string) ([]*entity.Tag, error)
+	GetRecentlyPublishedNews(limit int) ([]entity.News, error)
+	EnsureSearchIndex(language string) error
+	SearchNews(language, query string, includeUnpublished bool, limit, offset int) ([]NewsSearchHit, int64, error)
+	GetDueScheduledNews(now time.Time) ([]entity.News, error)
+	MarkNewsPublished(id uint64, publishAt time.Time) (bool, error)
 	UpdateNews(news *entity.News) error
+	UpdateNewsWithRevisions(news *entity.News, revisions ...*entity.NewsRevision) error
 	DeleteNews(id uint64) error
 }
 
@@ -25,16 +56,65 @@ func NewNewsRepository(db *gorm.DB) NewsRepository {
 	return &newsRepository{db}
 }
 
-func (r *newsRepository) CreateNews(news *entity.News) error {
-	if err := r.db.Create(news).Error; err != nil {
-		return err
+// Markers placed around search matches by SearchNews. They are swapped for <mark> tags once the
+// surrounding text has been escaped.
+const (
+	HighlightStart = "⟦"
+	HighlightStop  = "⟧"
+)
+
+// visibleNews restricts a query to news that players are allowed to read right now.
+func visibleNews(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("news.status = ?", enums.NewsStatusPublished).
+			Where("news.publish_at IS NULL OR news.publish_at <= ?", now).
+			Where("news.expires_at IS NULL OR news.expires_at > ?", now)
 	}
-	return nil
 }
 
-func (r *newsRepository) GetNewsByID(id uint64) (*entity.News, error) {
+func (r *newsRepository) scoped(includeUnpublished bool) *gorm.DB {
+	query := r.db.Preload("Category").Preload("Tags")
+	if includeUnpublished {
+		return query
+	}
+	return query.Scopes(visibleNews(time.Now()))
+}
+
+func (r *newsRepository) filtered(filter NewsFilter) *gorm.DB {
+	query := r.scoped(filter.IncludeUnpublished)
+	if filter.CategorySlug != "" {
+		query = query.Joins("JOIN news_categories ON news_categories.id = news.category_id").
+			Where("news_categories.slug = ?", filter.CategorySlug)
+	}
+	if filter.Tag != "" {
+		query = query.Where("news.id IN (?)", r.db.Table("news_tags").
+			Select("news_tags.news_id").
+			Joins("JOIN tags ON tags.id = news_tags.tag_id").
+			Where("tags.name = ?", filter.Tag))
+	}
+	return query
+}
+
+// CreateNews stores the news together with its first revision.
+func (r *newsRepository) CreateNews(news *entity.News) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category").Create(news).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.NewsRevision{
+			NewsID:   news.ID,
+			Version:  1,
+			EditedBy: news.CreatedBy,
+			Title:    news.Title,
+			Content:  news.Content,
+			ImageURL: news.ImageURL,
+		}).Error
+	})
+}
+
+func (r *newsRepository) GetNewsByID(id uint64, includeUnpublished bool) (*entity.News, error) {
 	var news entity.News
-	if err := r.db.First(&news, id).Error; err != nil {
+	if err := r.scoped(includeUnpublished).First(&news, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -43,29 +123,212 @@ func (r *newsRepository) GetNewsByID(id uint64) (*entity.News, error) {
 	return &news, nil
 }
 
-func (r *newsRepository) GetAllNews() ([]entity.News, error) {
+func (r *newsRepository) GetAllNews(filter NewsFilter) ([]entity.News, error) {
 	var news []entity.News
-	if err := r.db.Find(&news).Error; err != nil {
+	if err := r.filtered(filter).Find(&news).Error; err != nil {
 		return nil, err
 	}
 	return news, nil
 }
 
-func (r *newsRepository) GetLatestNews() ([]entity.News, error) {
+// GetLatestNews returns the newest news first, keeping pinned news at the top.
+func (r *newsRepository) GetLatestNews(filter NewsFilter) ([]entity.News, error) {
 	var news []entity.News
-	if err := r.db.Order("created_at DESC").Find(&news).Error; err != nil {
+	if err := r.filtered(filter).Order("news.pinned DESC, news.created_at DESC").Find(&news).Error; err != nil {
 		return nil, err
 	}
 	return news, nil
 }
 
-func (r *newsRepository) UpdateNews(news *entity.News) error {
-	return r.db.Save(news).Error
+// GetRecentlyPublishedNews returns the visible news ordered by publication date, newest first.
+func (r *newsRepository) GetRecentlyPublishedNews(limit int) ([]entity.News, error) {
+	var news []entity.News
+	err := r.scoped(false).
+		Order("COALESCE(news.publish_at, news.created_at) DESC").
+		Limit(limit).
+		Find(&news).Error
+	return news, err
 }
 
-func (r *newsRepository) DeleteNews(id uint64) error {
-	if err := r.db.Delete(&entity.News{}, id).Error; err != nil {
+// GetOrCreateTags returns the tags with the given names, creating the missing ones.
+func (r *newsRepository) GetOrCreateTags(names []string) ([]*entity.Tag, error) {
+	tags := make([]*entity.Tag, 0, len(names))
+	for _, name := range names {
+		tag := entity.Tag{Name: name}
+		if err := r.db.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, nil
+}
+
+// GetDueScheduledNews returns scheduled news whose publish time has already passed.
+func (r *newsRepository) GetDueScheduledNews(now time.Time) ([]entity.News, error) {
+	var news []entity.News
+	err := r.db.Where("status = ? AND publish_at <= ?", enums.NewsStatusScheduled, now).
+		Order("publish_at ASC").
+		Find(&news).Error
+	return news, err
+}
+
+// MarkNewsPublished flips a scheduled or draft news to published. It reports false when another
+// worker already published it, so notifications are only sent once.
+func (r *newsRepository) MarkNewsPublished(id uint64, publishAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.News{}).
+		Where("id = ? AND status <> ?", id, enums.NewsStatusPublished).
+		Updates(map[string]interface{}{
+			"status":     enums.NewsStatusPublished,
+			"publish_at": publishAt,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// searchConfigPattern guards the text search configuration name, which has to be inlined in DDL.
+var searchConfigPattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// EnsureSearchIndex creates the generated tsvector column over title and content and its GIN
+// index. The column is rebuilt when the text search configuration (language) changes.
+func (r *newsRepository) EnsureSearchIndex(language string) error {
+	if !searchConfigPattern.MatchString(language) {
+		return fmt.Errorf("invalid text search configuration: %q", language)
+	}
+
+	var exists bool
+	if err := r.db.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", language).Scan(&exists).Error; err != nil {
 		return err
 	}
-	return nil
+	if !exists {
+		return fmt.Errorf("text search configuration %q is not installed", language)
+	}
+
+	var current *string
+	err := r.db.Raw(`SELECT col_description('news'::regclass, attnum) FROM pg_attribute
+		WHERE attrelid = 'news'::regclass AND attname = 'search_vector' AND NOT attisdropped`).Scan(&current).Error
+	if err != nil {
+		return err
+	}
+	if current != nil && *current == language {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"ALTER TABLE news DROP COLUMN IF EXISTS search_vector",
+			fmt.Sprintf(`ALTER TABLE news ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('%[1]s', coalesce(content, '')), 'B')) STORED`, language),
+			"CREATE INDEX IF NOT EXISTS idx_news_search_vector ON news USING GIN (search_vector)",
+			fmt.Sprintf("COMMENT ON COLUMN news.search_vector IS '%s'", language),
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SearchNews runs a web-style full-text query (quoted phrases, OR, -exclusions) ranked by relevance.
+// Matches in the title and snippet are wrapped in the markers used by HighlightStart/HighlightStop.
+func (r *newsRepository) SearchNews(language, query string, includeUnpublished bool, limit, offset int) ([]NewsSearchHit, int64, error) {
+	base := r.db.Table("news").
+		Joins("CROSS JOIN websearch_to_tsquery(?::regconfig, ?) AS query", language, query).
+		Where("news.search_vector @@ query")
+	if !includeUnpublished {
+		base = base.Scopes(visibleNews(time.Now()))
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	titleOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", HighlightStart, HighlightStop)
+	snippetOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \"", HighlightStart, HighlightStop)
+
+	var hits []NewsSearchHit
+	err := base.Session(&gorm.Session{}).
+		Select(`news.*,
+			ts_rank_cd(news.search_vector, query) AS rank,
+			ts_headline(?::regconfig, news.title, query, ?) AS title_highlight,
+			ts_headline(?::regconfig, news.content, query, ?) AS snippet`,
+			language, titleOptions, language, snippetOptions).
+		Order("rank DESC, news.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+func (r *newsRepository) UpdateNews(news *entity.News) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "Tags").Save(news).Error; err != nil {
+			return err
+		}
+		return tx.Model(news).Association("Tags").Replace(news.Tags)
+	})
+}
+
+// UpdateNewsWithRevisions saves the news and appends the revisions describing the change atomically.
+func (r *newsRepository) UpdateNewsWithRevisions(news *entity.News, revisions ...*entity.NewsRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "Tags").Save(news).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(news).Association("Tags").Replace(news.Tags); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			version, err := nextRevisionVersion(tx, news.ID)
+			if err != nil {
+				return err
+			}
+			revision.NewsID = news.ID
+			revision.Version = version
+			if err := tx.Create(revision).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteNews removes the news with its revisions, translations, tag links, comments and their
+// reports, reactions and views, so nothing is left pointing at it and the images they referenced
+// can be garbage collected.
+func (r *newsRepository) DeleteNews(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		comments := tx.Model(&entity.Comment{}).Select("id").Where("news_id = ?", id)
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&entity.CommentReport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("news_id = ?", id).Delete(&entity.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("news_id = ?", id).Delete(&entity.Reaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("news_id = ?", id).Delete(&entity.NewsView{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("news_id = ?", id).Delete(&entity.NewsRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("news_id = ?", id).Delete(&entity.NewsTranslation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM news_tags WHERE news_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.News{}, id).Error
+	})
 }
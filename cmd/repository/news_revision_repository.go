@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+)
+
+type NewsRevisionRepository interface {
+	GetRevisionsByNews(newsID uint64) ([]entity.NewsRevision, error)
+	GetRevision(newsID, revisionID uint64) (*entity.NewsRevision, error)
+	GetLatestRevision(newsID uint64) (*entity.NewsRevision, error)
+}
+
+type newsRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsRevisionRepository(db *gorm.DB) NewsRevisionRepository {
+	return &newsRevisionRepository{db}
+}
+
+// GetRevisionsByNews lists the revisions of a news, newest first, without their full content.
+func (r *newsRevisionRepository) GetRevisionsByNews(newsID uint64) ([]entity.NewsRevision, error) {
+	var revisions []entity.NewsRevision
+	err := r.db.Omit("content").
+		Where("news_id = ?", newsID).
+		Order("version DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *newsRevisionRepository) GetRevision(newsID, revisionID uint64) (*entity.NewsRevision, error) {
+	var revision entity.NewsRevision
+	if err := r.db.Where("news_id = ? AND id = ?", newsID, revisionID).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *newsRevisionRepository) GetLatestRevision(newsID uint64) (*entity.NewsRevision, error) {
+	var revision entity.NewsRevision
+	if err := r.db.Where("news_id = ?", newsID).Order("version DESC").First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// nextRevisionVersion returns the version number for the next revision of a news inside tx.
+func nextRevisionVersion(tx *gorm.DB, newsID uint64) (int, error) {
+	var latest int
+	err := tx.Model(&entity.NewsRevision{}).
+		Where("news_id = ?", newsID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	return latest + 1, err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NewsTranslationRepository interface {
+	GetTranslationsByNewsIDs(newsIDs []uint64) ([]entity.NewsTranslation, error)
+	GetTranslation(newsID uint64, locale string) (*entity.NewsTranslation, error)
+	SaveTranslation(translation *entity.NewsTranslation) error
+	DeleteTranslation(newsID uint64, locale string) error
+}
+
+type newsTranslationRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsTranslationRepository(db *gorm.DB) NewsTranslationRepository {
+	return &newsTranslationRepository{db}
+}
+
+func (r *newsTranslationRepository) GetTranslationsByNewsIDs(newsIDs []uint64) ([]entity.NewsTranslation, error) {
+	var translations []entity.NewsTranslation
+	if len(newsIDs) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("news_id IN ?", newsIDs).Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+func (r *newsTranslationRepository) GetTranslation(newsID uint64, locale string) (*entity.NewsTranslation, error) {
+	var translation entity.NewsTranslation
+	if err := r.db.Where("news_id = ? AND locale = ?", newsID, locale).First(&translation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// SaveTranslation inserts the translation or replaces the existing one for the same news and locale.
+func (r *newsTranslationRepository) SaveTranslation(translation *entity.NewsTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "news_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "content_html", "excerpt", "updated_by", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *newsTranslationRepository) DeleteTranslation(newsID uint64, locale string) error {
+	return r.db.Where("news_id = ? AND locale = ?", newsID, locale).Delete(&entity.NewsTranslation{}).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"venecraft-back/cmd/entity"
+)
+
+// notificationBatchSize bounds the rows inserted per statement when notifying many users.
+const notificationBatchSize = 500
+
+type NotificationRepository interface {
+	CreateNotifications(notifications []entity.Notification) error
+	GetNotificationByID(id uint64) (*entity.Notification, error)
+	GetNotificationPage(userID uint64, unreadOnly bool, limit, offset int) ([]entity.Notification, int64, error)
+	CountUnread(userID uint64) (int64, error)
+	MarkRead(userID, id uint64) error
+	MarkAllRead(userID uint64) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+func (r *notificationRepository) CreateNotifications(notifications []entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&notifications, notificationBatchSize).Error
+}
+
+func (r *notificationRepository) GetNotificationByID(id uint64) (*entity.Notification, error) {
+	var notification entity.Notification
+	if err := r.db.First(&notification, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) GetNotificationPage(userID uint64, unreadOnly bool, limit, offset int) ([]entity.Notification, int64, error) {
+	query := r.db.Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []entity.Notification
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks the notification as read; notifications already read keep their read time.
+func (r *notificationRepository) MarkRead(userID, id uint64) error {
+	return r.db.Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now()).Error
+}
+
+// MarkAllRead marks every unread notification of the user as read and returns how many there were.
+func (r *notificationRepository) MarkAllRead(userID uint64) (int64, error) {
+	result := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
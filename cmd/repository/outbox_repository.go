@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+
+	"gorm.io/gorm"
+)
+
+// OutboxFilter holds the optional filters of the outbox listing.
+type OutboxFilter struct {
+	Status string
+	Kind   string
+}
+
+type OutboxRepository interface {
+	EnqueueEmails(emails []entity.OutboxEmail) error
+	ClaimDueEmails(now time.Time, lease time.Duration, limit int) ([]entity.OutboxEmail, error)
+	UpdateEmail(email *entity.OutboxEmail) error
+	GetEmailByID(id uint64) (*entity.OutboxEmail, error)
+	GetEmailPage(filter OutboxFilter, limit, offset int) ([]entity.OutboxEmail, int64, error)
+	CountEmailsByStatus() (map[string]int64, error)
+	DeleteSentEmails(sentBefore time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db}
+}
+
+// enqueueEmails writes emails to the outbox with the given connection, so repositories can queue
+// them in the same transaction as the change they notify about.
+func enqueueEmails(tx *gorm.DB, emails []entity.OutboxEmail) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range emails {
+		emails[i].Status = enums.EmailStatusPending
+		if emails[i].NextAttemptAt.IsZero() {
+			emails[i].NextAttemptAt = now
+		}
+	}
+	return tx.Create(&emails).Error
+}
+
+func (r *outboxRepository) EnqueueEmails(emails []entity.OutboxEmail) error {
+	return enqueueEmails(r.db, emails)
+}
+
+// ClaimDueEmails locks the emails due for delivery and leases them to the caller. Emails left in
+// the sending state by a crashed dispatcher become due again once their lease expires. Concurrent
+// dispatchers never claim the same email.
+func (r *outboxRepository) ClaimDueEmails(now time.Time, lease time.Duration, limit int) ([]entity.OutboxEmail, error) {
+	var emails []entity.OutboxEmail
+	err := r.db.Raw(`
+		UPDATE outbox_emails
+		SET status = ?, attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_emails
+			WHERE status IN (?, ?) AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		enums.EmailStatusSending, now.Add(lease), now,
+		enums.EmailStatusPending, enums.EmailStatusSending, now, limit).
+		Scan(&emails).Error
+	return emails, err
+}
+
+func (r *outboxRepository) UpdateEmail(email *entity.OutboxEmail) error {
+	return r.db.Save(email).Error
+}
+
+func (r *outboxRepository) GetEmailByID(id uint64) (*entity.OutboxEmail, error) {
+	var email entity.OutboxEmail
+	if err := r.db.First(&email, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &email, nil
+}
+
+func (r *outboxRepository) GetEmailPage(filter OutboxFilter, limit, offset int) ([]entity.OutboxEmail, int64, error) {
+	query := r.db.Model(&entity.OutboxEmail{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var emails []entity.OutboxEmail
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&emails).Error
+	return emails, total, err
+}
+
+func (r *outboxRepository) CountEmailsByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&entity.OutboxEmail{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteSentEmails removes the emails delivered before the given moment.
+func (r *outboxRepository) DeleteSentEmails(sentBefore time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND sent_at < ?", enums.EmailStatusSent, sentBefore).Delete(&entity.OutboxEmail{})
+	return result.RowsAffected, result.Error
+}
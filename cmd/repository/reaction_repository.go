@@ -2,14 +2,23 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"venecraft-back/cmd/entity"
 )
 
+// ReactionCount is the number of reactions of one type on a news.
+type ReactionCount struct {
+	NewsID uint64
+	Type   string
+	Count  int64
+}
+
 type ReactionRepository interface {
-	CreateReaction(reaction *entity.Reaction) error
-	HasUserReacted(userID, newsID uint64, reactionType string) (bool, error)
-	DeleteReaction(userID, newsID uint64, reactionType string) error
-	GetReactionCountForNews(newsID uint64, reactionType string) (int64, error)
+	GetReactionCounts(newsIDs []uint64) ([]ReactionCount, error)
+	GetUserReactions(userID uint64, newsIDs []uint64) ([]entity.Reaction, error)
+	ToggleReaction(reaction *entity.Reaction, exclusiveTypes []string) (bool, bool, []string, error)
+	CountReactionsByType(reactionType string) (int64, error)
+	RemoveDuplicateReactions() error
 }
 
 type reactionRepository struct {
@@ -20,26 +29,83 @@ func NewReactionRepository(db *gorm.DB) ReactionRepository {
 	return &reactionRepository{db}
 }
 
-func (r *reactionRepository) CreateReaction(reaction *entity.Reaction) error {
-	return r.db.Create(reaction).Error
+func (r *reactionRepository) GetReactionCounts(newsIDs []uint64) ([]ReactionCount, error) {
+	var counts []ReactionCount
+	if len(newsIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.Model(&entity.Reaction{}).
+		Select("news_id, type, COUNT(*) AS count").
+		Where("news_id IN ?", newsIDs).
+		Group("news_id, type").
+		Scan(&counts).Error
+	return counts, err
 }
 
-func (r *reactionRepository) HasUserReacted(userID, newsID uint64, reactionType string) (bool, error) {
-	var count int64
-	err := r.db.Model(&entity.Reaction{}).
-		Where("user_id = ? AND news_id = ? AND type = ?", userID, newsID, reactionType).
-		Count(&count).Error
-	return count > 0, err
+func (r *reactionRepository) GetUserReactions(userID uint64, newsIDs []uint64) ([]entity.Reaction, error) {
+	var reactions []entity.Reaction
+	if userID == 0 || len(newsIDs) == 0 {
+		return reactions, nil
+	}
+	err := r.db.Where("user_id = ? AND news_id IN ?", userID, newsIDs).Find(&reactions).Error
+	return reactions, err
 }
 
-func (r *reactionRepository) DeleteReaction(userID, newsID uint64, reactionType string) error {
-	return r.db.Where("user_id = ? AND news_id = ? AND type = ?", userID, newsID, reactionType).Delete(&entity.Reaction{}).Error
+// ToggleReaction removes the reaction when the user already reacted with it, otherwise it adds it
+// and removes the user's reactions of the given mutually exclusive types. It reports whether the
+// user has the reaction afterwards, whether this call changed it, and which exclusive reactions
+// were removed. Losing the race to a concurrent toggle adding the same reaction leaves it unchanged.
+func (r *reactionRepository) ToggleReaction(reaction *entity.Reaction, exclusiveTypes []string) (bool, bool, []string, error) {
+	reacted, changed := false, false
+	var removed []string
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND news_id = ? AND type = ?", reaction.UserID, reaction.NewsID, reaction.Type).
+			Delete(&entity.Reaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			changed = true
+			return nil
+		}
+
+		if len(exclusiveTypes) > 0 {
+			var siblings []entity.Reaction
+			err := tx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "type"}}}).
+				Where("user_id = ? AND news_id = ? AND type IN ?", reaction.UserID, reaction.NewsID, exclusiveTypes).
+				Delete(&siblings).Error
+			if err != nil {
+				return err
+			}
+			for _, sibling := range siblings {
+				removed = append(removed, sibling.Type)
+			}
+		}
+
+		// A concurrent toggle may have inserted the same reaction; the unique index keeps one row.
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		reacted, changed = true, result.RowsAffected == 1
+		return nil
+	})
+	return reacted, changed, removed, err
 }
 
-func (r *reactionRepository) GetReactionCountForNews(newsID uint64, reactionType string) (int64, error) {
+func (r *reactionRepository) CountReactionsByType(reactionType string) (int64, error) {
 	var count int64
-	err := r.db.Model(&entity.Reaction{}).
-		Where("news_id = ? AND type = ?", newsID, reactionType).
-		Count(&count).Error
+	err := r.db.Model(&entity.Reaction{}).Where("type = ?", reactionType).Count(&count).Error
 	return count, err
 }
+
+// RemoveDuplicateReactions keeps the oldest of each user/news/type reaction so the unique index
+// can be created on databases that collected duplicates from concurrent toggles.
+func (r *reactionRepository) RemoveDuplicateReactions() error {
+	if !r.db.Migrator().HasTable(&entity.Reaction{}) {
+		return nil
+	}
+	return r.db.Exec(`DELETE FROM reactions a USING reactions b
+		WHERE a.user_id = b.user_id AND a.news_id = b.news_id AND a.type = b.type AND a.id > b.id`).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+
+	"venecraft-back/cmd/entity"
+
+	"gorm.io/gorm"
+)
+
+type ReactionTypeRepository interface {
+	CreateReactionType(reactionType *entity.ReactionType) error
+	GetReactionTypes(includeInactive bool) ([]entity.ReactionType, error)
+	GetReactionTypeByKey(key string) (*entity.ReactionType, error)
+	UpdateReactionType(reactionType *entity.ReactionType) error
+	DeleteReactionType(id uint64) error
+}
+
+type reactionTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionTypeRepository(db *gorm.DB) ReactionTypeRepository {
+	return &reactionTypeRepository{db}
+}
+
+func (r *reactionTypeRepository) CreateReactionType(reactionType *entity.ReactionType) error {
+	return r.db.Create(reactionType).Error
+}
+
+func (r *reactionTypeRepository) GetReactionTypes(includeInactive bool) ([]entity.ReactionType, error) {
+	var reactionTypes []entity.ReactionType
+	query := r.db.Order("sort_order ASC, id ASC")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+	err := query.Find(&reactionTypes).Error
+	return reactionTypes, err
+}
+
+func (r *reactionTypeRepository) GetReactionTypeByKey(key string) (*entity.ReactionType, error) {
+	var reactionType entity.ReactionType
+	if err := r.db.Where("key = ?", key).First(&reactionType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &reactionType, nil
+}
+
+func (r *reactionTypeRepository) UpdateReactionType(reactionType *entity.ReactionType) error {
+	return r.db.Model(&entity.ReactionType{}).
+		Where("id = ?", reactionType.ID).
+		Updates(map[string]interface{}{
+			"emoji":           reactionType.Emoji,
+			"label":           reactionType.Label,
+			"exclusive_group": reactionType.ExclusiveGroup,
+			"sort_order":      reactionType.SortOrder,
+			"active":          reactionType.Active,
+		}).Error
+}
+
+func (r *reactionTypeRepository) DeleteReactionType(id uint64) error {
+	return r.db.Delete(&entity.ReactionType{}, id).Error
+}
@@ -1,16 +1,23 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"gorm.io/gorm"
 	"venecraft-back/cmd/entity"
 )
 
 type RegisterRepository interface {
-	CreateRegister(register *entity.Register) error
+	CreateRegister(register *entity.Register, verification *entity.EmailVerification, emails ...entity.OutboxEmail) error
+	GetRegisterByEmail(email string) (*entity.Register, error)
 	GetAllRegisters() ([]entity.Register, error)
+	CountPendingRegisters() (awaitingApproval, awaitingVerification int64, err error)
 	GetRegisterByID(id uint64) (*entity.Register, error)
-	DeleteRegister(id uint64) error
+	ApproveRegister(id uint64, user *entity.User, roleID uint64, emails ...entity.OutboxEmail) error
+	DeleteRegister(id uint64, emails ...entity.OutboxEmail) error
 	UpdateRegister(register *entity.Register) error
+	ReserveVerificationResend(id uint64, now time.Time, cooldown, window time.Duration, limit int) (bool, error)
 }
 
 type registerRepository struct {
@@ -21,16 +28,71 @@ func NewRegisterRepository(db *gorm.DB) RegisterRepository {
 	return &registerRepository{db}
 }
 
-func (r *registerRepository) CreateRegister(register *entity.Register) error {
-	return r.db.Create(register).Error
+// CreateRegister stores the registration request with the verification of its email address and
+// queues the emails about it in one transaction. Requests whose verification expired are removed
+// first, so their email and nickname can be registered again.
+func (r *registerRepository) CreateRegister(register *entity.Register, verification *entity.EmailVerification, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteUnverifiedRegisters(tx, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Create(register).Error; err != nil {
+			return err
+		}
+		if verification != nil {
+			verification.SubjectID = register.ID
+			if err := createVerification(tx, verification); err != nil {
+				return err
+			}
+		}
+		return enqueueEmails(tx, emails)
+	})
 }
 
+// GetAllRegisters returns the registration requests whose email address was verified.
 func (r *registerRepository) GetAllRegisters() ([]entity.Register, error) {
 	var registers []entity.Register
-	err := r.db.Find(&registers).Error
+	err := r.db.Where("awaiting_verification = ?", false).Find(&registers).Error
 	return registers, err
 }
 
+// CountPendingRegisters counts the requests waiting for an admin and those whose email address is
+// not verified yet.
+func (r *registerRepository) CountPendingRegisters() (int64, int64, error) {
+	var rows []struct {
+		AwaitingVerification bool
+		Count                int64
+	}
+	err := r.db.Model(&entity.Register{}).
+		Select("awaiting_verification, COUNT(*) AS count").
+		Group("awaiting_verification").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var awaitingApproval, awaitingVerification int64
+	for _, row := range rows {
+		if row.AwaitingVerification {
+			awaitingVerification = row.Count
+		} else {
+			awaitingApproval = row.Count
+		}
+	}
+	return awaitingApproval, awaitingVerification, nil
+}
+
+func (r *registerRepository) GetRegisterByEmail(email string) (*entity.Register, error) {
+	var register entity.Register
+	if err := r.db.Where("email = ?", email).First(&register).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &register, nil
+}
+
 func (r *registerRepository) GetRegisterByID(id uint64) (*entity.Register, error) {
 	var reg entity.Register
 	err := r.db.First(&reg, id).Error
@@ -40,10 +102,54 @@ func (r *registerRepository) GetRegisterByID(id uint64) (*entity.Register, error
 	return &reg, nil
 }
 
-func (r *registerRepository) DeleteRegister(id uint64) error {
-	return r.db.Delete(&entity.Register{}, id).Error
+// ApproveRegister creates the user of an accepted registration request with its role, removes the
+// request and queues the emails about it in one transaction.
+func (r *registerRepository) ApproveRegister(id uint64, user *entity.User, roleID uint64, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&entity.UserRole{UserID: user.ID, RoleID: roleID}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity.Register{}, id).Error; err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
+}
+
+func (r *registerRepository) DeleteRegister(id uint64, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.Register{}, id).Error; err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
 }
 
 func (r *registerRepository) UpdateRegister(register *entity.Register) error {
 	return r.db.Save(register).Error
 }
+
+// ReserveVerificationResend records a verification resend for a request awaiting verification. It
+// reports false, recording nothing, when the latest email was sent less than cooldown ago or limit
+// resends were already made in the current window. The check and the update are one statement, so
+// concurrent resends cannot both pass.
+func (r *registerRepository) ReserveVerificationResend(id uint64, now time.Time, cooldown, window time.Duration, limit int) (bool, error) {
+	windowStart := now.Add(-window)
+	windowExpired := gorm.Expr("verification_resend_window IS NULL OR verification_resend_window <= ?", windowStart)
+	result := r.db.Model(&entity.Register{}).
+		Where("id = ? AND awaiting_verification = ?", id, true).
+		Where("verification_sent_at IS NULL OR verification_sent_at <= ?", now.Add(-cooldown)).
+		Where(r.db.Where(windowExpired).Or("verification_resends < ?", limit)).
+		UpdateColumns(map[string]interface{}{
+			"verification_sent_at":       now,
+			"verification_resend_window": gorm.Expr("CASE WHEN ? THEN ? ELSE verification_resend_window END", windowExpired, now),
+			"verification_resends":       gorm.Expr("CASE WHEN ? THEN 1 ELSE verification_resends + 1 END", windowExpired),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
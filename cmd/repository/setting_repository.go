@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"venecraft-back/cmd/entity"
+)
+
+type SettingRepository interface {
+	GetSettings() ([]entity.Setting, error)
+	GetSettingByKey(key string) (*entity.Setting, error)
+	GetUserSettings(userID uint64) ([]entity.UserSetting, error)
+	SaveUserSettings(userSettings []entity.UserSetting) error
+	GetSettingOverrides(settingID uint64) (map[uint64]bool, error)
+}
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) SettingRepository {
+	return &settingRepository{db}
+}
+
+func (r *settingRepository) GetSettings() ([]entity.Setting, error) {
+	var settings []entity.Setting
+	err := r.db.Order("sort_order ASC, id ASC").Find(&settings).Error
+	return settings, err
+}
+
+func (r *settingRepository) GetSettingByKey(key string) (*entity.Setting, error) {
+	var setting entity.Setting
+	if err := r.db.Where("key = ?", key).First(&setting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// GetUserSettings returns the settings the user changed from the defaults of their roles.
+func (r *settingRepository) GetUserSettings(userID uint64) ([]entity.UserSetting, error) {
+	var userSettings []entity.UserSetting
+	err := r.db.Where("user_id = ?", userID).Find(&userSettings).Error
+	return userSettings, err
+}
+
+// SaveUserSettings stores the user's choices, replacing the previous ones for the same settings.
+func (r *settingRepository) SaveUserSettings(userSettings []entity.UserSetting) error {
+	if len(userSettings) == 0 {
+		return nil
+	}
+	for i := range userSettings {
+		userSettings[i].UpdatedAt = time.Now()
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "setting_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_active", "updated_at"}),
+	}).Create(&userSettings).Error
+}
+
+// GetSettingOverrides returns the choice of each user that changed the setting, by user ID.
+func (r *settingRepository) GetSettingOverrides(settingID uint64) (map[uint64]bool, error) {
+	var userSettings []entity.UserSetting
+	if err := r.db.Where("setting_id = ?", settingID).Find(&userSettings).Error; err != nil {
+		return nil, err
+	}
+	overrides := make(map[uint64]bool, len(userSettings))
+	for _, userSetting := range userSettings {
+		overrides[userSetting.UserID] = userSetting.IsActive
+	}
+	return overrides, nil
+}
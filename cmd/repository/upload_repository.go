@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+
+	"gorm.io/gorm"
+)
+
+type UploadRepository interface {
+	CreateUpload(slot *entity.UploadSlot) error
+	GetUploadByID(id uint64) (*entity.UploadSlot, error)
+	UpdateUpload(slot *entity.UploadSlot) error
+	DeleteExpiredUploads(expiredBefore time.Time) (int64, error)
+}
+
+type uploadRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadRepository(db *gorm.DB) UploadRepository {
+	return &uploadRepository{db}
+}
+
+func (r *uploadRepository) CreateUpload(slot *entity.UploadSlot) error {
+	return r.db.Create(slot).Error
+}
+
+func (r *uploadRepository) GetUploadByID(id uint64) (*entity.UploadSlot, error) {
+	var slot entity.UploadSlot
+	if err := r.db.First(&slot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *uploadRepository) UpdateUpload(slot *entity.UploadSlot) error {
+	return r.db.Save(slot).Error
+}
+
+// DeleteExpiredUploads removes the slots that were never completed before their deadline. Their
+// files, if any, are left to the media garbage collector.
+func (r *uploadRepository) DeleteExpiredUploads(expiredBefore time.Time) (int64, error) {
+	result := r.db.Where("status <> ? AND expires_at < ?", enums.UploadStatusCompleted, expiredBefore).
+		Delete(&entity.UploadSlot{})
+	return result.RowsAffected, result.Error
+}
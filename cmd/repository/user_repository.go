@@ -10,7 +10,7 @@ type UserRepository interface {
 	CreateUser(user *entity.User) error
 	GetAllUsers() ([]entity.User, error)
 	GetUserByID(id uint64) (*entity.User, error)
-	UpdateUser(user *entity.User) error
+	UpdateUser(user *entity.User, emails ...entity.OutboxEmail) error
 	DeleteUser(id uint64) error
 	GetUserByEmail(email string, preloadRoles bool) (*entity.User, error)
 	GetUserByNickname(nickname string) (*entity.User, error)
@@ -18,6 +18,7 @@ type UserRepository interface {
 	GetUserByResetToken(resetToken string) (*entity.User, error)
 	HasRole(id uint64, role string) bool
 	CountActiveUsers() (int, error)
+	GetActiveUsers() ([]entity.User, error)
 }
 
 type userRepository struct {
@@ -47,18 +48,27 @@ func (r *userRepository) GetUserByID(id uint64) (*entity.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) UpdateUser(user *entity.User) error {
-	return r.db.Model(&entity.User{}).
-		Where("id = ?", user.ID).
-		Updates(map[string]interface{}{
-			"full_name":                      user.FullName,
-			"email":                          user.Email,
-			"nickname":                       user.Nickname,
-			"password":                       user.Password,
-			"recover_password_token":         user.RecoverPasswordToken,
-			"recover_password_token_expires": user.RecoverPasswordTokenExpires,
-			"is_active":                      user.IsActive,
-		}).Error
+// UpdateUser saves the user and queues the given emails in the same transaction.
+func (r *userRepository) UpdateUser(user *entity.User, emails ...entity.OutboxEmail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.User{}).
+			Where("id = ?", user.ID).
+			Updates(map[string]interface{}{
+				"full_name":                      user.FullName,
+				"email":                          user.Email,
+				"nickname":                       user.Nickname,
+				"password":                       user.Password,
+				"recover_password_token":         user.RecoverPasswordToken,
+				"recover_password_token_expires": user.RecoverPasswordTokenExpires,
+				"is_active":                      user.IsActive,
+				"locale":                         user.Locale,
+				"avatar_url":                     user.AvatarURL,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return enqueueEmails(tx, emails)
+	})
 }
 
 func (r *userRepository) DeleteUser(id uint64) error {
@@ -128,3 +138,9 @@ func (r *userRepository) CountActiveUsers() (int, error) {
 	err := r.db.Model(&entity.User{}).Where("is_active = ?", true).Count(&count).Error
 	return int(count), err
 }
+
+func (r *userRepository) GetActiveUsers() ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("is_active = ?", true).Preload("Roles").Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CommentRoutes(router *gin.RouterGroup, commentController *controller.CommentController) {
+	router.GET("/news/:id/comments", commentController.GetNewsComments)
+	router.POST("/news/:id/comments", commentController.CreateComment)
+
+	commentGroup := router.Group("/comments")
+	{
+		commentGroup.PUT("/:id", commentController.UpdateComment)
+		commentGroup.DELETE("/:id", commentController.DeleteComment)
+		commentGroup.PUT("/:id/hide", commentController.HideComment)
+		commentGroup.PUT("/:id/unhide", commentController.UnhideComment)
+		commentGroup.POST("/:id/report", commentController.ReportComment)
+	}
+
+	reportGroup := router.Group("/comment-reports")
+	{
+		reportGroup.GET("/", commentController.GetReports)
+		reportGroup.PUT("/:id/resolve", commentController.ResolveReport)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EmailOutboxRoutes(router *gin.RouterGroup, emailOutboxController *controller.EmailOutboxController) {
+	emailGroup := router.Group("/emails")
+	{
+		emailGroup.GET("/", emailOutboxController.GetEmails)
+		emailGroup.GET("/:id", emailOutboxController.GetEmail)
+		emailGroup.POST("/:id/resend", emailOutboxController.ResendEmail)
+	}
+}
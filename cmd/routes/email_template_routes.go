@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EmailTemplateRoutes(router *gin.RouterGroup, emailTemplateController *controller.EmailTemplateController) {
+	templateGroup := router.Group("/email-templates")
+	{
+		templateGroup.GET("/", emailTemplateController.GetTemplates)
+		templateGroup.GET("/preview", emailTemplateController.PreviewTemplate)
+	}
+}
@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"venecraft-back/cmd/controller"
+)
+
+func EmailVerificationRoutes(router *gin.Engine, emailVerificationController *controller.EmailVerificationController) {
+	router.POST("/api/verify-email", emailVerificationController.VerifyEmail)
+}
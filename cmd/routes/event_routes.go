@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EventRoutes(router *gin.Engine, eventController *controller.EventController, tickets *events.TicketStore) {
+	router.POST("/api/events/ticket", middlewares.AuthMiddleware(), eventController.CreateStreamTicket)
+
+	eventGroup := router.Group("/api/events")
+	eventGroup.Use(middlewares.StreamAuthMiddleware(tickets))
+	{
+		eventGroup.GET("", eventController.StreamEvents)
+		eventGroup.GET("/ws", eventController.StreamEventsWebSocket)
+	}
+}
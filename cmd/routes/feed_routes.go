@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"venecraft-back/cmd/controller"
+)
+
+func FeedRoutes(router *gin.Engine, feedController *controller.FeedController) {
+	feedGroup := router.Group("/feeds")
+	{
+		feedGroup.GET("/news.rss", feedController.NewsRSS)
+		feedGroup.HEAD("/news.rss", feedController.NewsRSS)
+		feedGroup.GET("/news.atom", feedController.NewsAtom)
+		feedGroup.HEAD("/news.atom", feedController.NewsAtom)
+		feedGroup.GET("/news.json", feedController.NewsJSON)
+		feedGroup.HEAD("/news.json", feedController.NewsJSON)
+	}
+}
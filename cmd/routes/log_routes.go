@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func LogRoutes(router *gin.RouterGroup, logController *controller.LogController) {
+	logGroup := router.Group("/audit-logs")
+	{
+		logGroup.GET("/", logController.GetLogs)
+		logGroup.GET("/verify", logController.VerifyLogs)
+		logGroup.GET("/export", logController.ExportLogs)
+		logGroup.GET("/archives", logController.GetArchives)
+		logGroup.GET("/:id", logController.GetLog)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MediaRoutes(router *gin.RouterGroup, mediaController *controller.MediaController) {
+	mediaGroup := router.Group("/media")
+	{
+		mediaGroup.POST("/", mediaController.UploadMedia)
+		mediaGroup.GET("/", mediaController.GetMedia)
+		mediaGroup.GET("/:id", mediaController.GetMediaByID)
+		mediaGroup.DELETE("/:id", mediaController.DeleteMedia)
+	}
+}
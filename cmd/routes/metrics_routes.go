@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+	"venecraft-back/cmd/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsRoutes serves the Prometheus metrics on /metrics. When a token is given, scrapers must
+// send it as a bearer token.
+func MetricsRoutes(router *gin.Engine, handler http.Handler, token string) {
+	handlers := []gin.HandlerFunc{gin.WrapH(handler)}
+	if token != "" {
+		handlers = append([]gin.HandlerFunc{middlewares.MetricsTokenMiddleware(token)}, handlers...)
+	}
+	router.GET("/metrics", handlers...)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewsAnalyticsRoutes(router *gin.RouterGroup, newsAnalyticsController *controller.NewsAnalyticsController) {
+	router.GET("/news/analytics", newsAnalyticsController.GetAnalyticsOverview)
+	router.GET("/news/:id/analytics", newsAnalyticsController.GetNewsAnalytics)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewsCategoryRoutes(router *gin.RouterGroup, newsCategoryController *controller.NewsCategoryController) {
+	categoryGroup := router.Group("/news-categories")
+	{
+		categoryGroup.GET("/", newsCategoryController.GetAllCategories)
+		categoryGroup.POST("/", newsCategoryController.CreateCategory)
+		categoryGroup.PUT("/:id", newsCategoryController.UpdateCategory)
+		categoryGroup.DELETE("/:id", newsCategoryController.DeleteCategory)
+	}
+}
@@ -12,9 +12,14 @@ func NewsRoutes(router *gin.RouterGroup, newsController *controller.NewsControll
 		newsGroup.POST("/", newsController.CreateNews)
 		newsGroup.GET("/", newsController.GetAllNews)
 		newsGroup.GET("/latest/", newsController.GetLatestNews)
+		newsGroup.GET("/search", newsController.SearchNews)
 		newsGroup.GET("/:id", newsController.GetNewsByID)
 		newsGroup.PUT("/:id", newsController.UpdateNews)
 		newsGroup.DELETE("/:id", newsController.DeleteNews)
-		newsGroup.POST("/:id/reaction/:reactionType", newsController.ToggleReaction)
+		newsGroup.PUT("/:id/publish", newsController.PublishNews)
+		newsGroup.PUT("/:id/archive", newsController.ArchiveNews)
+		newsGroup.GET("/:id/revisions", newsController.GetNewsRevisions)
+		newsGroup.GET("/:id/revisions/:revisionID", newsController.GetNewsRevision)
+		newsGroup.POST("/:id/revisions/:revisionID/restore", newsController.RestoreNewsRevision)
 	}
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewsTranslationRoutes(router *gin.RouterGroup, newsTranslationController *controller.NewsTranslationController) {
+	translationGroup := router.Group("/news/:id/translations")
+	{
+		translationGroup.GET("", newsTranslationController.GetTranslations)
+		translationGroup.PUT("/:locale", newsTranslationController.SaveTranslation)
+		translationGroup.DELETE("/:locale", newsTranslationController.DeleteTranslation)
+	}
+}
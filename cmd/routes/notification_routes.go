@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NotificationRoutes(router *gin.RouterGroup, notificationController *controller.NotificationController) {
+	notificationGroup := router.Group("/me/notifications")
+	{
+		notificationGroup.GET("/", notificationController.GetMyNotifications)
+		notificationGroup.GET("/unread-count", notificationController.GetUnreadCount)
+		notificationGroup.POST("/read-all", notificationController.MarkAllRead)
+		notificationGroup.POST("/:id/read", notificationController.MarkRead)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReactionRoutes(router *gin.RouterGroup, reactionController *controller.ReactionController) {
+	router.POST("/news/:id/reaction/:reactionType", reactionController.ToggleReaction)
+
+	reactionTypeGroup := router.Group("/reaction-types")
+	{
+		reactionTypeGroup.GET("/", reactionController.GetReactionTypes)
+		reactionTypeGroup.POST("/", reactionController.CreateReactionType)
+		reactionTypeGroup.PUT("/:key", reactionController.UpdateReactionType)
+		reactionTypeGroup.DELETE("/:key", reactionController.DeleteReactionType)
+	}
+}
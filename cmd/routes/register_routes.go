@@ -7,4 +7,5 @@ import (
 
 func RegisterRoutes(router *gin.Engine, registerController *controller.RegisterController) {
 	router.POST("/api/register", registerController.CreateRegister)
+	router.POST("/api/register/resend-verification", registerController.ResendVerification)
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SettingRoutes(router *gin.RouterGroup, settingController *controller.SettingController) {
+	meGroup := router.Group("/me")
+	{
+		meGroup.GET("/settings", settingController.GetMySettings)
+		meGroup.PUT("/settings", settingController.UpdateMySettings)
+	}
+}
+
+func UnsubscribeRoutes(router *gin.Engine, settingController *controller.SettingController) {
+	router.POST("/api/unsubscribe", settingController.Unsubscribe)
+}
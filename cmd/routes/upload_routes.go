@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"venecraft-back/cmd/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func UploadRoutes(router *gin.RouterGroup, uploadController *controller.UploadController) {
+	uploadGroup := router.Group("/uploads")
+	{
+		uploadGroup.POST("/", uploadController.CreateUpload)
+		uploadGroup.POST("/:id/confirm", uploadController.ConfirmUpload)
+	}
+}
@@ -0,0 +1,23 @@
+package seeds
+
+import (
+	"gorm.io/gorm"
+	"log"
+	"venecraft-back/cmd/entity"
+)
+
+func SeedNewsCategories(db *gorm.DB) {
+	categories := []entity.NewsCategory{
+		{Name: "Updates", Slug: "updates", Description: "Game and launcher updates"},
+		{Name: "Events", Slug: "events", Description: "Community events and tournaments"},
+		{Name: "Maintenance", Slug: "maintenance", Description: "Planned downtime and maintenance windows"},
+	}
+
+	for _, category := range categories {
+		err := db.Where("slug = ?", category.Slug).FirstOrCreate(&category).Error
+		if err != nil {
+			log.Fatalf("Error seeding news categories: %v", err)
+		}
+		log.Printf("News category %s seeded successfully", category.Slug)
+	}
+}
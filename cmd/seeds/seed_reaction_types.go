@@ -0,0 +1,26 @@
+package seeds
+
+import (
+	"gorm.io/gorm"
+	"log"
+	"venecraft-back/cmd/entity"
+)
+
+func SeedReactionTypes(db *gorm.DB) {
+	reactionTypes := []entity.ReactionType{
+		{Key: "like", Emoji: "👍", Label: "Like", ExclusiveGroup: "vote", SortOrder: 1, Active: true},
+		{Key: "dislike", Emoji: "👎", Label: "Dislike", ExclusiveGroup: "vote", SortOrder: 2, Active: true},
+		{Key: "love", Emoji: "❤️", Label: "Love", SortOrder: 3, Active: true},
+		{Key: "laugh", Emoji: "😂", Label: "Laugh", SortOrder: 4, Active: true},
+		{Key: "wow", Emoji: "😮", Label: "Wow", SortOrder: 5, Active: true},
+		{Key: "sad", Emoji: "😢", Label: "Sad", SortOrder: 6, Active: true},
+	}
+
+	for _, reactionType := range reactionTypes {
+		err := db.Where("key = ?", reactionType.Key).FirstOrCreate(&reactionType).Error
+		if err != nil {
+			log.Fatalf("Error seeding reaction types: %v", err)
+		}
+		log.Printf("Reaction type %s seeded successfully", reactionType.Key)
+	}
+}
@@ -0,0 +1,45 @@
+package seeds
+
+import (
+	"gorm.io/gorm"
+	"log"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+)
+
+func SeedSettings(db *gorm.DB) {
+	settings := []entity.Setting{
+		{
+			Key:          enums.SettingNewsPublished,
+			Description:  "News articles are published",
+			DefaultRoles: []string{enums.RolePlayer, enums.RoleMod, enums.RoleAdmin},
+			SortOrder:    1,
+		},
+		{
+			Key:          enums.SettingRegistrationStatus,
+			Description:  "Registration requests are received or change status",
+			DefaultRoles: []string{enums.RolePlayer, enums.RoleMod, enums.RoleAdmin},
+			SortOrder:    2,
+		},
+		{
+			Key:          enums.SettingModerationActions,
+			Description:  "Moderators act on your content or on reports",
+			DefaultRoles: []string{enums.RolePlayer, enums.RoleMod, enums.RoleAdmin},
+			SortOrder:    3,
+		},
+		{
+			Key:          enums.SettingSecurityAlerts,
+			Description:  "Your email address, password or roles change",
+			DefaultRoles: []string{enums.RolePlayer, enums.RoleMod, enums.RoleAdmin},
+			SortOrder:    4,
+		},
+	}
+
+	for _, setting := range settings {
+		err := db.Where("key = ?", setting.Key).FirstOrCreate(&setting).Error
+		if err != nil {
+			log.Fatalf("Error seeding settings: %v", err)
+		}
+		log.Printf("Setting %s seeded successfully", setting.Key)
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type AdminService interface {
-	CreateUser(user *entity.User, role string) error
+	CreateUser(actor AuditActor, user *entity.User, role string) error
 	UpdateUser(user *entity.User) error
 	DeleteUser(userID uint64) error
 	GetUserByID(userID uint64) (*entity.User, error)
@@ -18,14 +18,14 @@ type AdminService interface {
 	DeletePlayer(playerID uint64) error
 	GetPlayerByID(playerID uint64) (*entity.Player, error)
 
-	CreateModerator(mod *entity.User) error
+	CreateModerator(actor AuditActor, mod *entity.User) error
 	UpdateModerator(mod *entity.User) error
 	DeleteModerator(modID uint64) error
 	GetModeratorByID(modID uint64) (*entity.User, error)
 
 	BanPlayer(playerID uint64, reason string, duration time.Duration) error
 
-	CreateNews(news *entity.News) error
+	CreateNews(actor AuditActor, news *entity.News) error
 	GetAllNews() ([]entity.News, error)
 	GetLatestNews() ([]entity.News, error)
 	GetNewsByID(id uint64) (*entity.News, error)
@@ -55,8 +55,8 @@ func NewAdminService(userRepo repository.UserRepository, newsRepo repository.New
 	}
 }
 
-func (s *adminService) CreateUser(user *entity.User, role string) error {
-	return s.userService.CreateUser(user, role)
+func (s *adminService) CreateUser(actor AuditActor, user *entity.User, role string) error {
+	return s.userService.CreateUser(actor, user, role)
 }
 
 func (s *adminService) UpdateUser(user *entity.User) error {
@@ -95,8 +95,8 @@ func (s *adminService) GetPlayerByID(playerID uint64) (*entity.Player, error) {
 	return player, nil
 }
 
-func (s *adminService) CreateModerator(mod *entity.User) error {
-	return s.userService.CreateUser(mod, "MODERATOR")
+func (s *adminService) CreateModerator(actor AuditActor, mod *entity.User) error {
+	return s.userService.CreateUser(actor, mod, "MODERATOR")
 }
 
 func (s *adminService) UpdateModerator(mod *entity.User) error {
@@ -143,21 +143,21 @@ func (s *adminService) BanPlayer(playerID uint64, reason string, duration time.D
 	return nil
 }
 
-func (s *adminService) CreateNews(news *entity.News) error {
-	return s.newsService.CreateNews(news)
+func (s *adminService) CreateNews(actor AuditActor, news *entity.News) error {
+	return s.newsService.CreateNews(actor, news)
 }
 
 func (s *adminService) GetAllNews() ([]entity.News, error) {
-	return s.newsRepo.GetAllNews()
+	return s.newsRepo.GetAllNews(repository.NewsFilter{IncludeUnpublished: true})
 }
 
 func (s *adminService) GetLatestNews() ([]entity.News, error) {
-	return s.newsRepo.GetLatestNews()
+	return s.newsRepo.GetLatestNews(repository.NewsFilter{IncludeUnpublished: true})
 }
 
 func (s *adminService) GetNewsByID(newsID uint64) (*entity.News, error) {
-	news, err := s.newsRepo.GetNewsByID(newsID)
-	if err != nil {
+	news, err := s.newsRepo.GetNewsByID(newsID, true)
+	if err != nil || news == nil {
 		return nil, errors.New("news not found")
 	}
 	return news, nil
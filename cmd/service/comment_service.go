@@ -0,0 +1,447 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+// maxCommentLength is the longest comment accepted, in characters.
+const maxCommentLength = 2000
+
+var (
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrCommentForbidden   = errors.New("you are not allowed to modify this comment")
+	ErrCommentRateLimited = errors.New("you are commenting too fast, please wait before posting again")
+	ErrReportNotFound     = errors.New("comment report not found")
+	ErrAlreadyReported    = errors.New("you already reported this comment")
+)
+
+// CommentViewer describes who is reading or acting on comments.
+type CommentViewer struct {
+	UserID uint64
+
+	// IsModerator grants access to hidden comments and moderation actions.
+	IsModerator bool
+
+	// Request is recorded in the audit log with the moderation actions.
+	Request RequestInfo
+}
+
+func (v CommentViewer) auditActor() AuditActor {
+	return AuditActor{UserID: v.UserID, RequestInfo: v.Request}
+}
+
+// CommentNode is a comment with its replies, as returned to the launcher.
+type CommentNode struct {
+	ID             uint64         `json:"id"`
+	NewsID         uint64         `json:"news_id"`
+	UserID         uint64         `json:"user_id"`
+	AuthorNickname string         `json:"author_nickname"`
+	ParentID       *uint64        `json:"parent_id"`
+	Content        string         `json:"content"`
+	Hidden         bool           `json:"hidden"`
+	HiddenReason   string         `json:"hidden_reason,omitempty"`
+	Deleted        bool           `json:"deleted"`
+	EditedAt       *time.Time     `json:"edited_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Replies        []*CommentNode `json:"replies"`
+}
+
+type CommentService interface {
+	CreateComment(userID, newsID uint64, parentID *uint64, content string) (*entity.Comment, error)
+	GetCommentsForNews(viewer CommentViewer, newsID uint64) ([]*CommentNode, error)
+	UpdateComment(viewer CommentViewer, commentID uint64, content string) (*entity.Comment, error)
+	DeleteComment(viewer CommentViewer, commentID uint64) error
+	HideComment(viewer CommentViewer, commentID uint64, reason string) error
+	UnhideComment(viewer CommentViewer, commentID uint64) error
+	ReportComment(userID, commentID uint64, reason string) error
+	GetReports(status string) ([]entity.CommentReport, error)
+	ResolveReport(viewer CommentViewer, reportID uint64, action string) error
+}
+
+type commentService struct {
+	commentRepo repository.CommentRepository
+	newsRepo    repository.NewsRepository
+	logService  LogService
+	notifier    NotificationService
+	publisher   events.Publisher
+	rateLimit   int
+	rateWindow  time.Duration
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, newsRepo repository.NewsRepository, logService LogService, notifier NotificationService, publisher events.Publisher) CommentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		newsRepo:    newsRepo,
+		logService:  logService,
+		notifier:    notifier,
+		publisher:   publisher,
+		rateLimit:   utils.GetEnvInt("COMMENT_RATE_LIMIT", 5),
+		rateWindow:  utils.GetEnvDuration("COMMENT_RATE_WINDOW", time.Minute),
+	}
+}
+
+func (s *commentService) CreateComment(userID, newsID uint64, parentID *uint64, content string) (*entity.Comment, error) {
+	content, err := validateCommentContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := s.newsRepo.GetNewsByID(newsID, false)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
+
+	if parentID != nil {
+		parent, err := s.commentRepo.GetCommentByID(*parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.NewsID != newsID || parent.Deleted || parent.Hidden {
+			return nil, errors.New("the comment you are replying to is not available")
+		}
+	}
+
+	recent, err := s.commentRepo.CountUserCommentsSince(userID, time.Now().Add(-s.rateWindow))
+	if err != nil {
+		return nil, err
+	}
+	if s.rateLimit > 0 && recent >= int64(s.rateLimit) {
+		return nil, ErrCommentRateLimited
+	}
+
+	comment := &entity.Comment{
+		NewsID:    newsID,
+		UserID:    userID,
+		ParentID:  parentID,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+	if err := s.commentRepo.CreateComment(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetCommentsForNews returns the comment threads of a news. Hidden comments are only shown
+// to moderators; deleted or hidden comments that still have replies are kept as placeholders.
+func (s *commentService) GetCommentsForNews(viewer CommentViewer, newsID uint64) ([]*CommentNode, error) {
+	news, err := s.newsRepo.GetNewsByID(newsID, viewer.IsModerator)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
+
+	comments, err := s.commentRepo.GetCommentsByNews(newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint64]*CommentNode, len(comments))
+	ordered := make([]*CommentNode, 0, len(comments))
+	for _, comment := range comments {
+		node := &CommentNode{
+			ID:             comment.ID,
+			NewsID:         comment.NewsID,
+			UserID:         comment.UserID,
+			AuthorNickname: comment.AuthorNickname,
+			ParentID:       comment.ParentID,
+			Content:        comment.Content,
+			Hidden:         comment.Hidden,
+			Deleted:        comment.Deleted,
+			EditedAt:       comment.EditedAt,
+			CreatedAt:      comment.CreatedAt,
+			Replies:        []*CommentNode{},
+		}
+		if comment.Hidden && viewer.IsModerator {
+			node.HiddenReason = comment.HiddenReason
+		}
+		if comment.Deleted || (comment.Hidden && !viewer.IsModerator) {
+			node.Content = ""
+		}
+		nodes[comment.ID] = node
+		ordered = append(ordered, node)
+	}
+
+	var roots []*CommentNode
+	for _, node := range ordered {
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return pruneCommentNodes(roots, viewer.IsModerator), nil
+}
+
+// pruneCommentNodes drops removed comments that no longer have any visible reply.
+func pruneCommentNodes(nodes []*CommentNode, isModerator bool) []*CommentNode {
+	kept := make([]*CommentNode, 0, len(nodes))
+	for _, node := range nodes {
+		node.Replies = pruneCommentNodes(node.Replies, isModerator)
+		removed := node.Deleted || (node.Hidden && !isModerator)
+		if removed && len(node.Replies) == 0 {
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept
+}
+
+func (s *commentService) UpdateComment(viewer CommentViewer, commentID uint64, content string) (*entity.Comment, error) {
+	comment, err := s.getActiveComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != viewer.UserID {
+		return nil, ErrCommentForbidden
+	}
+	if comment.Hidden {
+		return nil, errors.New("hidden comments cannot be edited")
+	}
+
+	content, err = validateCommentContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment.Content = content
+	comment.EditedAt = &now
+	if err := s.commentRepo.UpdateComment(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment. Authors can delete their own comments and moderators any comment.
+func (s *commentService) DeleteComment(viewer CommentViewer, commentID uint64) error {
+	comment, err := s.getActiveComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != viewer.UserID && !viewer.IsModerator {
+		return ErrCommentForbidden
+	}
+	previous := *comment
+
+	comment.Deleted = true
+	comment.DeletedBy = &viewer.UserID
+	comment.Content = ""
+	if err := s.commentRepo.UpdateComment(comment); err != nil {
+		return err
+	}
+
+	if comment.UserID != viewer.UserID {
+		if err := s.commentRepo.ResolveOpenReportsForComment(comment.ID, viewer.UserID, enums.CommentReportResolved); err != nil {
+			return err
+		}
+		s.notifier.NotifyUser(comment.UserID, enums.NotificationCommentDeleted, commentNotificationData(comment))
+		s.publishModeration("comment_deleted", viewer.UserID, comment, nil)
+		s.logService.Audit(viewer.auditActor(), enums.AuditCommentDelete, enums.AuditTargetComment, comment.ID, &previous, comment)
+	}
+	return nil
+}
+
+func (s *commentService) HideComment(viewer CommentViewer, commentID uint64, reason string) error {
+	if !viewer.IsModerator {
+		return ErrCommentForbidden
+	}
+	comment, err := s.getActiveComment(commentID)
+	if err != nil {
+		return err
+	}
+	previous := *comment
+
+	comment.Hidden = true
+	comment.HiddenBy = &viewer.UserID
+	comment.HiddenReason = strings.TrimSpace(reason)
+	if err := s.commentRepo.UpdateComment(comment); err != nil {
+		return err
+	}
+	if err := s.commentRepo.ResolveOpenReportsForComment(comment.ID, viewer.UserID, enums.CommentReportResolved); err != nil {
+		return err
+	}
+
+	data := commentNotificationData(comment)
+	data["reason"] = comment.HiddenReason
+	s.notifier.NotifyUser(comment.UserID, enums.NotificationCommentHidden, data)
+	s.publishModeration("comment_hidden", viewer.UserID, comment, nil)
+	s.logService.Audit(viewer.auditActor(), enums.AuditCommentHide, enums.AuditTargetComment, comment.ID, &previous, comment)
+	return nil
+}
+
+func (s *commentService) UnhideComment(viewer CommentViewer, commentID uint64) error {
+	if !viewer.IsModerator {
+		return ErrCommentForbidden
+	}
+	comment, err := s.getActiveComment(commentID)
+	if err != nil {
+		return err
+	}
+	previous := *comment
+
+	comment.Hidden = false
+	comment.HiddenBy = nil
+	comment.HiddenReason = ""
+	if err := s.commentRepo.UpdateComment(comment); err != nil {
+		return err
+	}
+
+	s.notifier.NotifyUser(comment.UserID, enums.NotificationCommentRestored, commentNotificationData(comment))
+	s.publishModeration("comment_restored", viewer.UserID, comment, nil)
+	s.logService.Audit(viewer.auditActor(), enums.AuditCommentUnhide, enums.AuditTargetComment, comment.ID, &previous, comment)
+	return nil
+}
+
+func (s *commentService) ReportComment(userID, commentID uint64, reason string) error {
+	comment, err := s.getActiveComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID == userID {
+		return errors.New("you cannot report your own comment")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason must be provided")
+	}
+	if utf8.RuneCountInString(reason) > 255 {
+		return errors.New("the reason must be at most 255 characters long")
+	}
+
+	reported, err := s.commentRepo.HasUserReported(userID, commentID)
+	if err != nil {
+		return err
+	}
+	if reported {
+		return ErrAlreadyReported
+	}
+
+	report := &entity.CommentReport{
+		CommentID:  commentID,
+		ReporterID: userID,
+		Reason:     reason,
+		Status:     enums.CommentReportOpen,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.commentRepo.CreateReport(report); err != nil {
+		return err
+	}
+	s.publishModeration("comment_reported", 0, comment, report)
+	return nil
+}
+
+func (s *commentService) GetReports(status string) ([]entity.CommentReport, error) {
+	return s.commentRepo.GetReportsByStatus(status)
+}
+
+// ResolveReport handles a report. The action is "dismiss" to keep the comment, or "hide"/"delete"
+// to act on the reported comment.
+func (s *commentService) ResolveReport(viewer CommentViewer, reportID uint64, action string) error {
+	if !viewer.IsModerator {
+		return ErrCommentForbidden
+	}
+	report, err := s.commentRepo.GetReportByID(reportID)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return ErrReportNotFound
+	}
+	if report.Status != enums.CommentReportOpen {
+		return errors.New("the report was already handled")
+	}
+
+	switch action {
+	case "hide":
+		err = s.HideComment(viewer, report.CommentID, report.Reason)
+	case "delete":
+		err = s.DeleteComment(viewer, report.CommentID)
+	case "dismiss":
+		previous := *report
+		now := time.Now()
+		report.Status = enums.CommentReportDismissed
+		report.ResolvedBy = &viewer.UserID
+		report.ResolvedAt = &now
+		if err = s.commentRepo.UpdateReport(report); err == nil {
+			s.publishModeration("report_dismissed", viewer.UserID, nil, report)
+			s.logService.Audit(viewer.auditActor(), enums.AuditReportDismiss, enums.AuditTargetReport, report.ID, &previous, report)
+		}
+	default:
+		return errors.New("invalid action, expected dismiss, hide or delete")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.notifier.NotifyUser(report.ReporterID, enums.NotificationReportResolved, map[string]interface{}{
+		"report_id":  report.ID,
+		"comment_id": report.CommentID,
+		"dismissed":  action == "dismiss",
+	})
+	return nil
+}
+
+func (s *commentService) getActiveComment(commentID uint64) (*entity.Comment, error) {
+	comment, err := s.commentRepo.GetCommentByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.Deleted {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func commentNotificationData(comment *entity.Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"comment_id": comment.ID,
+		"news_id":    comment.NewsID,
+	}
+}
+
+// publishModeration announces a moderation event to the moderators on the event stream.
+func (s *commentService) publishModeration(action string, moderatorID uint64, comment *entity.Comment, report *entity.CommentReport) {
+	data := map[string]interface{}{"action": action}
+	if moderatorID != 0 {
+		data["moderator_id"] = moderatorID
+	}
+	if comment != nil {
+		data["comment_id"] = comment.ID
+		data["news_id"] = comment.NewsID
+	}
+	if report != nil {
+		data["report_id"] = report.ID
+		data["comment_id"] = report.CommentID
+	}
+	s.publisher.Publish(enums.TopicModeration, data)
+}
+
+func validateCommentContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("the comment content must be provided")
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return "", fmt.Errorf("the comment must be at most %d characters long", maxCommentLength)
+	}
+	return content, nil
+}
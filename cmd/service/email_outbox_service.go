@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+	"venecraft-back/cmd/email"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrEmailNotFound      = errors.New("email not found")
+	ErrEmailNotResendable = errors.New("only emails that could not be delivered can be resent")
+	ErrEmailLinkExpired   = errors.New("emails with one-time links cannot be resent, the user must request a new one")
+)
+
+// oneTimeLinkEmails are the kinds of email carrying a password reset or verification token. The
+// token may have expired by the time the email is dead, so they are never resent and their bodies
+// are cleared.
+var oneTimeLinkEmails = map[string]bool{
+	"password_reset/password_reset": true,
+	"register/verify_email":         true,
+	"account/confirm_email_change":  true,
+}
+
+// outboxBatchSize is the number of emails claimed per dispatcher query.
+const outboxBatchSize = 50
+
+// OutboxPage is one page of the email outbox.
+type OutboxPage struct {
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+	HasMore  bool                 `json:"has_more"`
+	Items    []entity.OutboxEmail `json:"items"`
+}
+
+type EmailOutboxService interface {
+	DispatchDueEmails(ctx context.Context) (sent int, failed int, err error)
+	GetEmails(filter repository.OutboxFilter, page, pageSize int) (*OutboxPage, error)
+	GetEmail(id uint64) (*entity.OutboxEmail, error)
+	ResendEmail(id uint64) (*entity.OutboxEmail, error)
+	DeleteSentEmails() (int64, error)
+}
+
+type emailOutboxService struct {
+	outboxRepo repository.OutboxRepository
+	mailer     email.Mailer
+
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	sendTimeout time.Duration
+	retention   time.Duration
+}
+
+func NewEmailOutboxService(outboxRepo repository.OutboxRepository, mailer email.Mailer) EmailOutboxService {
+	return &emailOutboxService{
+		outboxRepo:  outboxRepo,
+		mailer:      mailer,
+		maxAttempts: utils.GetEnvInt("EMAIL_MAX_ATTEMPTS", 8),
+		baseDelay:   utils.GetEnvDuration("EMAIL_RETRY_BASE_DELAY", time.Minute),
+		maxDelay:    utils.GetEnvDuration("EMAIL_RETRY_MAX_DELAY", 6*time.Hour),
+		sendTimeout: utils.GetEnvDuration("EMAIL_SEND_TIMEOUT", 30*time.Second),
+		retention:   utils.GetEnvDuration("EMAIL_OUTBOX_RETENTION", 30*24*time.Hour),
+	}
+}
+
+// renderOutboxEmail renders the template in the recipient's locale and builds the outbox entry
+// for the message, whose subject and bodies come from the template.
+func renderOutboxEmail(templates *email.Templates, name, locale string, data interface{}, message email.Message) (*entity.OutboxEmail, error) {
+	rendered, err := templates.Render(name, locale, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OutboxEmail{
+		Kind:            rendered.Name,
+		Locale:          rendered.Locale,
+		TemplateVersion: rendered.Version,
+		From:            message.From,
+		To:              message.To,
+		Cc:              message.Cc,
+		Bcc:             message.Bcc,
+		ReplyTo:         message.ReplyTo,
+		Subject:         rendered.Subject,
+		HTML:            rendered.HTML,
+		Text:            rendered.Text,
+		Headers:         message.Headers,
+	}, nil
+}
+
+// DispatchDueEmails delivers the emails whose next attempt is due. Failed deliveries are retried
+// with exponential backoff until the maximum number of attempts, after which the email is dead.
+func (s *emailOutboxService) DispatchDueEmails(ctx context.Context) (int, int, error) {
+	sent, failed := 0, 0
+	for {
+		// The lease outlives the whole batch, so no other dispatcher picks it up in the meantime.
+		emails, err := s.outboxRepo.ClaimDueEmails(time.Now(), s.sendTimeout*(outboxBatchSize+1), outboxBatchSize)
+		if err != nil {
+			return sent, failed, err
+		}
+
+		for i := range emails {
+			if ctx.Err() != nil {
+				return sent, failed, ctx.Err()
+			}
+			if s.deliver(ctx, &emails[i]) {
+				sent++
+			} else {
+				failed++
+			}
+			if err := s.outboxRepo.UpdateEmail(&emails[i]); err != nil {
+				return sent, failed, err
+			}
+		}
+
+		if len(emails) < outboxBatchSize {
+			return sent, failed, nil
+		}
+	}
+}
+
+// deliver sends a claimed email and records the outcome on it. The bodies of delivered emails, and
+// of dead ones that cannot be resent, are cleared.
+func (s *emailOutboxService) deliver(ctx context.Context, outboxEmail *entity.OutboxEmail) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, s.sendTimeout)
+	defer cancel()
+
+	providerID, err := s.mailer.Send(sendCtx, &email.Message{
+		From:    outboxEmail.From,
+		To:      outboxEmail.To,
+		Cc:      outboxEmail.Cc,
+		Bcc:     outboxEmail.Bcc,
+		ReplyTo: outboxEmail.ReplyTo,
+		Subject: outboxEmail.Subject,
+		HTML:    outboxEmail.HTML,
+		Text:    outboxEmail.Text,
+		Headers: outboxEmail.Headers,
+	})
+	if err == nil {
+		now := time.Now()
+		outboxEmail.Status = enums.EmailStatusSent
+		outboxEmail.ProviderID = providerID
+		outboxEmail.SentAt = &now
+		outboxEmail.LastError = ""
+		outboxEmail.HTML, outboxEmail.Text = "", ""
+		return true
+	}
+
+	outboxEmail.LastError = err.Error()
+	if outboxEmail.Attempts >= s.maxAttempts {
+		outboxEmail.Status = enums.EmailStatusDead
+		if oneTimeLinkEmails[outboxEmail.Kind] {
+			outboxEmail.HTML, outboxEmail.Text = "", ""
+		}
+		log.Printf("Email %d (%s) dead after %d attempts: %v", outboxEmail.ID, outboxEmail.Kind, outboxEmail.Attempts, err)
+		return false
+	}
+	outboxEmail.Status = enums.EmailStatusPending
+	outboxEmail.NextAttemptAt = time.Now().Add(s.retryDelay(outboxEmail.Attempts))
+	log.Printf("Email %d (%s) attempt %d failed, retrying at %s: %v", outboxEmail.ID, outboxEmail.Kind,
+		outboxEmail.Attempts, outboxEmail.NextAttemptAt.Format(time.RFC3339), err)
+	return false
+}
+
+// retryDelay doubles the base delay for every failed attempt, up to the maximum delay. A jitter of
+// up to 10% spreads the retries of emails that failed together.
+func (s *emailOutboxService) retryDelay(attempts int) time.Duration {
+	delay := s.maxDelay
+	if shift := attempts - 1; shift < 32 {
+		delay = min(s.baseDelay<<shift, s.maxDelay)
+	}
+	if delay <= 0 {
+		delay = s.maxDelay
+	}
+	return delay + time.Duration(rand.Int64N(int64(delay)/10+1))
+}
+
+func (s *emailOutboxService) GetEmails(filter repository.OutboxFilter, page, pageSize int) (*OutboxPage, error) {
+	emails, total, err := s.outboxRepo.GetEmailPage(filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxPage{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+		Items:    emails,
+	}, nil
+}
+
+func (s *emailOutboxService) GetEmail(id uint64) (*entity.OutboxEmail, error) {
+	outboxEmail, err := s.outboxRepo.GetEmailByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if outboxEmail == nil {
+		return nil, ErrEmailNotFound
+	}
+	return outboxEmail, nil
+}
+
+// ResendEmail queues a dead email again with a fresh set of attempts. Emails with one-time links
+// are refused: their token may have expired.
+func (s *emailOutboxService) ResendEmail(id uint64) (*entity.OutboxEmail, error) {
+	outboxEmail, err := s.GetEmail(id)
+	if err != nil {
+		return nil, err
+	}
+	if outboxEmail.Status != enums.EmailStatusDead {
+		return nil, ErrEmailNotResendable
+	}
+	if oneTimeLinkEmails[outboxEmail.Kind] {
+		return nil, ErrEmailLinkExpired
+	}
+
+	outboxEmail.Status = enums.EmailStatusPending
+	outboxEmail.Attempts = 0
+	outboxEmail.NextAttemptAt = time.Now()
+	if err := s.outboxRepo.UpdateEmail(outboxEmail); err != nil {
+		return nil, err
+	}
+	return outboxEmail, nil
+}
+
+// DeleteSentEmails removes the emails delivered longer ago than the retention period.
+func (s *emailOutboxService) DeleteSentEmails() (int64, error) {
+	return s.outboxRepo.DeleteSentEmails(time.Now().Add(-s.retention))
+}
+
+// EmailDispatcher periodically delivers the emails waiting in the outbox and removes the old ones.
+type EmailDispatcher struct {
+	outboxService EmailOutboxService
+	interval      time.Duration
+}
+
+func NewEmailDispatcher(outboxService EmailOutboxService, interval time.Duration) *EmailDispatcher {
+	return &EmailDispatcher{outboxService: outboxService, interval: interval}
+}
+
+// Start runs the dispatcher in the background until the context is cancelled.
+func (d *EmailDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (d *EmailDispatcher) run(ctx context.Context) {
+	sent, failed, err := d.outboxService.DispatchDueEmails(ctx)
+	if err != nil {
+		log.Printf("Error dispatching outbox emails: %v", err)
+	}
+	if sent > 0 || failed > 0 {
+		log.Printf("Dispatched outbox emails: %d sent, %d failed", sent, failed)
+	}
+
+	deleted, err := d.outboxService.DeleteSentEmails()
+	if err != nil {
+		log.Printf("Error deleting sent outbox emails: %v", err)
+	}
+	if deleted > 0 {
+		log.Printf("Removed %d sent outbox emails", deleted)
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"venecraft-back/cmd/email"
+)
+
+type EmailTemplateService interface {
+	GetTemplates() []email.TemplateInfo
+	PreviewTemplate(name, locale string) (*email.Rendered, error)
+}
+
+type emailTemplateService struct {
+	templates *email.Templates
+}
+
+func NewEmailTemplateService(templates *email.Templates) EmailTemplateService {
+	return &emailTemplateService{templates: templates}
+}
+
+func (s *emailTemplateService) GetTemplates() []email.TemplateInfo {
+	return s.templates.List()
+}
+
+// PreviewTemplate renders a template in the locale with its sample data.
+func (s *emailTemplateService) PreviewTemplate(name, locale string) (*email.Rendered, error) {
+	data, err := s.templates.Sample(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.templates.Render(name, locale, data)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrVerificationInvalid = errors.New("invalid or expired verification")
+	ErrEmailTaken          = errors.New("email address is already in use")
+	ErrInvalidEmail        = errors.New("invalid email format")
+	ErrVerificationPending = errors.New("a registration request for this email is awaiting verification")
+)
+
+// maxVerificationAttempts is the number of wrong codes after which a verification can only be
+// completed with its link.
+const maxVerificationAttempts = 5
+
+type EmailVerificationService interface {
+	VerifyToken(token string) error
+	VerifyCode(email, code string) error
+}
+
+type emailVerificationService struct {
+	verificationRepo repository.EmailVerificationRepository
+	registerService  RegisterService
+	userService      UserService
+}
+
+func NewEmailVerificationService(verificationRepo repository.EmailVerificationRepository, registerService RegisterService, userService UserService) EmailVerificationService {
+	return &emailVerificationService{verificationRepo, registerService, userService}
+}
+
+// VerifyToken completes the verification the link with the token was sent for.
+func (s *emailVerificationService) VerifyToken(token string) error {
+	if token == "" {
+		return ErrVerificationInvalid
+	}
+	verification, err := s.verificationRepo.GetVerificationByTokenHash(utils.HashSecret(token))
+	if err != nil {
+		return err
+	}
+	if verification == nil || verification.ConsumedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return ErrVerificationInvalid
+	}
+	return s.complete(verification)
+}
+
+// VerifyCode completes the latest pending verification of the address with the code sent to it.
+// Each wrong code counts as an attempt.
+func (s *emailVerificationService) VerifyCode(email, code string) error {
+	email = strings.TrimSpace(email)
+	if email == "" || code == "" {
+		return ErrVerificationInvalid
+	}
+	verification, err := s.verificationRepo.GetPendingVerificationByEmail(email, time.Now())
+	if err != nil {
+		return err
+	}
+	if verification == nil || verification.Attempts >= maxVerificationAttempts {
+		return ErrVerificationInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashSecret(code)), []byte(verification.CodeHash)) != 1 {
+		if err := s.verificationRepo.IncrementAttempts(verification.ID); err != nil {
+			return err
+		}
+		return ErrVerificationInvalid
+	}
+	return s.complete(verification)
+}
+
+func (s *emailVerificationService) complete(verification *entity.EmailVerification) error {
+	switch verification.Purpose {
+	case enums.VerificationRegister:
+		return s.registerService.CompleteVerification(verification)
+	case enums.VerificationEmailChange:
+		return s.userService.CompleteEmailChange(verification)
+	default:
+		return ErrVerificationInvalid
+	}
+}
+
+// verificationSettings configures the links and codes sent to verify email addresses.
+type verificationSettings struct {
+	ttl time.Duration
+	url string
+
+	// resendCooldown is the least time between two emails for the same verification, and
+	// resendLimit the most resends in an hour.
+	resendCooldown time.Duration
+	resendLimit    int
+}
+
+func loadVerificationSettings() verificationSettings {
+	return verificationSettings{
+		ttl:            utils.GetEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		url:            utils.GetEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email"),
+		resendCooldown: utils.GetEnvDuration("EMAIL_VERIFICATION_RESEND_COOLDOWN", time.Minute),
+		resendLimit:    utils.GetEnvInt("EMAIL_VERIFICATION_RESEND_LIMIT", 5),
+	}
+}
+
+// issue creates a verification of the address with a new token and code. Only their hashes are
+// stored; the returned template data holds the link and code to email.
+func (v verificationSettings) issue(purpose string, subjectID uint64, address string) (*entity.EmailVerification, map[string]interface{}, error) {
+	token, err := utils.GenerateVerificationToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	code, err := utils.GenerateVerificationCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verification := &entity.EmailVerification{
+		Purpose:   purpose,
+		SubjectID: subjectID,
+		Email:     address,
+		TokenHash: utils.HashSecret(token),
+		CodeHash:  utils.HashSecret(code),
+		ExpiresAt: time.Now().Add(v.ttl),
+	}
+	return verification, map[string]interface{}{
+		"Link":      v.link(token),
+		"Code":      code,
+		"ExpiresAt": verification.ExpiresAt,
+	}, nil
+}
+
+func (v verificationSettings) link(token string) string {
+	separator := "?"
+	if strings.Contains(v.url, "?") {
+		separator = "&"
+	}
+	return v.url + separator + "token=" + url.QueryEscape(token)
+}
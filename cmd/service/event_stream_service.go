@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrInvalidTopic   = errors.New("invalid event topic")
+	ErrTopicForbidden = errors.New("event topic not allowed")
+)
+
+// moderatorTopics are the topics only moderators and admins may subscribe to.
+var moderatorTopics = []string{enums.TopicModeration}
+
+// StreamTicket opens one event stream for the user it was issued to.
+// swagger:model StreamTicket
+type StreamTicket struct {
+	// Value of the ticket query parameter
+	Ticket string `json:"ticket"`
+
+	// Moment the ticket can no longer be used
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type EventStreamService interface {
+	Subscribe(userID uint64, roles []string, topics []string, lastEventID uint64) (*events.Subscription, []events.Event, bool, error)
+	IssueTicket(userID uint64, roles []string) (*StreamTicket, error)
+	HeartbeatInterval() time.Duration
+}
+
+type eventStreamService struct {
+	bus       *events.Bus
+	tickets   *events.TicketStore
+	heartbeat time.Duration
+}
+
+func NewEventStreamService(bus *events.Bus, tickets *events.TicketStore) EventStreamService {
+	return &eventStreamService{
+		bus:       bus,
+		tickets:   tickets,
+		heartbeat: utils.GetEnvDuration("EVENT_HEARTBEAT_INTERVAL", 25*time.Second),
+	}
+}
+
+// Subscribe subscribes the user to the topics, or to every topic they may receive when none are
+// given. See events.Bus.Subscribe for resuming after lastEventID.
+func (s *eventStreamService) Subscribe(userID uint64, roles []string, topics []string, lastEventID uint64) (*events.Subscription, []events.Event, bool, error) {
+	isModerator := slices.Contains(roles, enums.RoleAdmin) || slices.Contains(roles, enums.RoleMod)
+	if len(topics) == 0 {
+		topics = []string{enums.TopicNewsPublished, enums.TopicNotification}
+		if isModerator {
+			topics = append(topics, moderatorTopics...)
+		}
+	}
+	for _, topic := range topics {
+		if !enums.IsValidEventTopic(topic) {
+			return nil, nil, false, fmt.Errorf("%w: %s", ErrInvalidTopic, topic)
+		}
+		if slices.Contains(moderatorTopics, topic) && !isModerator {
+			return nil, nil, false, fmt.Errorf("%w: %s", ErrTopicForbidden, topic)
+		}
+	}
+
+	subscription, missed, resumed := s.bus.Subscribe(topics, userID, lastEventID)
+	return subscription, missed, resumed, nil
+}
+
+// IssueTicket creates a single-use ticket for clients that cannot send the Authorization header
+// when opening a stream.
+func (s *eventStreamService) IssueTicket(userID uint64, roles []string) (*StreamTicket, error) {
+	ticket, expiresAt, err := s.tickets.Issue(userID, roles)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+func (s *eventStreamService) HeartbeatInterval() time.Duration {
+	return s.heartbeat
+}
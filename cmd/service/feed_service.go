@@ -0,0 +1,119 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+
+	"github.com/gorilla/feeds"
+)
+
+// NewsFeed is the public news feed together with the validators used for HTTP caching.
+type NewsFeed struct {
+	Feed         *feeds.Feed
+	ETag         string
+	LastModified time.Time
+}
+
+type FeedService interface {
+	GetNewsFeed() (*NewsFeed, error)
+}
+
+type feedService struct {
+	newsRepo    repository.NewsRepository
+	siteURL     string
+	title       string
+	description string
+	itemLimit   int
+}
+
+func NewFeedService(newsRepo repository.NewsRepository) FeedService {
+	return &feedService{
+		newsRepo:    newsRepo,
+		siteURL:     strings.TrimRight(utils.GetEnv("FEED_SITE_URL", "https://venecraft.jjar.lat"), "/"),
+		title:       utils.GetEnv("FEED_TITLE", "Venecraft News"),
+		description: utils.GetEnv("FEED_DESCRIPTION", "Announcements from the Venecraft team"),
+		itemLimit:   utils.GetEnvInt("FEED_ITEM_LIMIT", 20),
+	}
+}
+
+// GetNewsFeed builds the feed from the currently published news.
+func (s *feedService) GetNewsFeed() (*NewsFeed, error) {
+	newsList, err := s.newsRepo.GetRecentlyPublishedNews(s.itemLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &feeds.Feed{
+		Title:       s.title,
+		Link:        &feeds.Link{Href: s.siteURL},
+		Description: s.description,
+		Author:      &feeds.Author{Name: s.title},
+		Id:          s.siteURL + "/news",
+	}
+
+	hash := sha256.New()
+	var lastModified time.Time
+	for _, news := range newsList {
+		published := news.CreatedAt
+		if news.PublishAt != nil {
+			published = *news.PublishAt
+		}
+		updated := news.UpdatedAt
+		if updated.Before(published) {
+			updated = published
+		}
+		if updated.After(lastModified) {
+			lastModified = updated
+		}
+
+		feed.Items = append(feed.Items, s.feedItem(&news, published, updated))
+		_, _ = fmt.Fprintf(hash, "%d:%d;", news.ID, updated.UnixNano())
+	}
+
+	if lastModified.IsZero() {
+		lastModified = time.Unix(0, 0)
+	}
+	feed.Created = lastModified
+	feed.Updated = lastModified
+
+	return &NewsFeed{
+		Feed:         feed,
+		ETag:         `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`,
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}, nil
+}
+
+func (s *feedService) feedItem(news *entity.News, published, updated time.Time) *feeds.Item {
+	link := fmt.Sprintf("%s/news/%d", s.siteURL, news.ID)
+	contentHTML := news.ContentHTML
+	if contentHTML == "" {
+		contentHTML, _ = utils.RenderMarkdown(news.Content)
+	}
+
+	item := &feeds.Item{
+		Id:          link,
+		Title:       news.Title,
+		Link:        &feeds.Link{Href: link},
+		Description: news.Excerpt,
+		Content:     contentHTML,
+		Created:     published,
+		Updated:     updated,
+	}
+
+	if news.ImageURL != "" {
+		contentType := mime.TypeByExtension(path.Ext(news.ImageURL))
+		if !strings.HasPrefix(contentType, "image/") {
+			contentType = "image/jpeg"
+		}
+		item.Enclosure = &feeds.Enclosure{Url: news.ImageURL, Type: contentType, Length: "0"}
+	}
+	return item
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"venecraft-back/cmd/storage"
+	"venecraft-back/cmd/utils"
+)
+
+var ErrImageTooLarge = errors.New("image exceeds the maximum upload size")
+
+var (
+	// imageKeyPattern matches the key of an original image stored by the image pipeline.
+	imageKeyPattern = regexp.MustCompile(`^images/([0-9a-f]{64})\.(jpg|png)$`)
+
+	// imageVariantKeyPattern matches the key of a resized variant of an image.
+	imageVariantKeyPattern = regexp.MustCompile(`^images/([0-9a-f]{64})_[a-z]+\.(jpg|png)$`)
+)
+
+// StoredImage is an image stored by the upload pipeline along with its variants.
+type StoredImage struct {
+	Key         string            `json:"key"`
+	URL         string            `json:"url"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Hash        string            `json:"hash"`
+	Variants    map[string]string `json:"variants"`
+
+	// Reused is set when an identical image was already stored, in which case it must not be
+	// deleted if the upload is rolled back.
+	Reused bool `json:"-"`
+}
+
+type ImageService interface {
+	UploadImage(ctx context.Context, file io.Reader) (*StoredImage, error)
+	DeleteImage(ctx context.Context, image *StoredImage) error
+	VariantURLs(imageURL string) map[string]string
+	OriginalKey(key string) string
+	MaxSize() int64
+}
+
+type imageService struct {
+	storage storage.Storage
+	maxSize int64
+}
+
+func NewImageService(fileStorage storage.Storage) ImageService {
+	return &imageService{
+		storage: fileStorage,
+		maxSize: int64(utils.GetEnvInt("MAX_IMAGE_UPLOAD_SIZE", 10<<20)),
+	}
+}
+
+// UploadImage validates, re-encodes and resizes an uploaded image, then stores the original and
+// its variants under keys derived from the content hash, so identical uploads share their objects
+// and different images never overwrite each other.
+func (s *imageService) UploadImage(ctx context.Context, file io.Reader) (*StoredImage, error) {
+	data, err := io.ReadAll(io.LimitReader(file, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %v", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrImageTooLarge
+	}
+
+	processed, err := utils.ProcessImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := imageKey(processed.Hash, "", processed.Original.Extension)
+	image := &StoredImage{
+		Key:         key,
+		URL:         s.storage.URL(key),
+		ContentType: processed.Original.ContentType,
+		Size:        int64(len(processed.Original.Data)),
+		Width:       processed.Original.Width,
+		Height:      processed.Original.Height,
+		Hash:        processed.Hash,
+		Variants:    s.variantURLs(processed.Hash, processed.Original.Extension),
+	}
+
+	if _, err := s.storage.Stat(ctx, key); err == nil {
+		image.Reused = true
+		return image, nil
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	for name, variant := range processed.Variants {
+		variantKey := imageKey(processed.Hash, name, variant.Extension)
+		if err := s.storage.Put(ctx, variantKey, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			return nil, err
+		}
+	}
+	// The original goes last: its presence marks the whole set as stored.
+	original := processed.Original
+	if err := s.storage.Put(ctx, key, bytes.NewReader(original.Data), int64(len(original.Data)), original.ContentType); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteImage removes an image and its variants from the storage.
+func (s *imageService) DeleteImage(ctx context.Context, image *StoredImage) error {
+	match := imageKeyPattern.FindStringSubmatch(image.Key)
+	if match == nil {
+		return s.storage.Delete(ctx, image.Key)
+	}
+
+	if err := s.storage.Delete(ctx, image.Key); err != nil {
+		return err
+	}
+	for _, variant := range utils.ImageVariants {
+		if err := s.storage.Delete(ctx, imageKey(match[1], variant.Name, match[2])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VariantURLs returns the URLs of the original and resized variants of an image. Images uploaded
+// before the pipeline existed have no variants, so the original URL is used for all of them.
+func (s *imageService) VariantURLs(imageURL string) map[string]string {
+	if imageURL == "" {
+		return nil
+	}
+	if key, ok := storage.KeyFromURL(s.storage, imageURL); ok {
+		if match := imageKeyPattern.FindStringSubmatch(key); match != nil {
+			return s.variantURLs(match[1], match[2])
+		}
+	}
+
+	urls := map[string]string{"original": imageURL}
+	for _, variant := range utils.ImageVariants {
+		urls[variant.Name] = imageURL
+	}
+	return urls
+}
+
+// OriginalKey returns the key of the original image a variant was generated from. Other keys are
+// returned unchanged.
+func (s *imageService) OriginalKey(key string) string {
+	if match := imageVariantKeyPattern.FindStringSubmatch(key); match != nil {
+		return imageKey(match[1], "", match[2])
+	}
+	return key
+}
+
+// MaxSize is the largest image accepted by the pipeline, in bytes.
+func (s *imageService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *imageService) variantURLs(hash, extension string) map[string]string {
+	urls := map[string]string{"original": s.storage.URL(imageKey(hash, "", extension))}
+	for _, variant := range utils.ImageVariants {
+		urls[variant.Name] = s.storage.URL(imageKey(hash, variant.Name, extension))
+	}
+	return urls
+}
+
+func imageKey(hash, variant, extension string) string {
+	if variant == "" {
+		return fmt.Sprintf("images/%s.%s", hash, extension)
+	}
+	return fmt.Sprintf("images/%s_%s.%s", hash, variant, extension)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// LogArchiver periodically archives the log entries past their retention.
+type LogArchiver struct {
+	logService LogService
+	interval   time.Duration
+}
+
+func NewLogArchiver(logService LogService, interval time.Duration) *LogArchiver {
+	return &LogArchiver{logService: logService, interval: interval}
+}
+
+// Start runs the archiver in the background until the context is cancelled.
+func (a *LogArchiver) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+
+		for {
+			a.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *LogArchiver) run(ctx context.Context) {
+	archived, err := a.logService.ArchiveExpiredLogs(ctx)
+	if err != nil {
+		log.Printf("Error archiving audit log entries: %v", err)
+	}
+	if archived > 0 {
+		log.Printf("Archived %d audit log entries", archived)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// LogCheckpointer periodically writes a signed checkpoint of the audit log to the storage.
+type LogCheckpointer struct {
+	logService LogService
+	interval   time.Duration
+}
+
+func NewLogCheckpointer(logService LogService, interval time.Duration) *LogCheckpointer {
+	return &LogCheckpointer{logService: logService, interval: interval}
+}
+
+// Start runs the checkpointer in the background until the context is cancelled.
+func (c *LogCheckpointer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *LogCheckpointer) run(ctx context.Context) {
+	checkpoint, err := c.logService.WriteCheckpoint(ctx)
+	if err != nil {
+		log.Printf("Error writing audit log checkpoint: %v", err)
+		return
+	}
+	if checkpoint != nil {
+		log.Printf("Wrote audit log checkpoint at entry %d", checkpoint.LastLogID)
+	}
+}
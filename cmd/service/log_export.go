@@ -0,0 +1,109 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+)
+
+// logCSVHeader lists the columns of the CSV exports and archives of the log.
+var logCSVHeader = []string{
+	"id", "timestamp", "user_id", "action", "description", "target_type", "target_id",
+	"before", "after", "ip", "user_agent", "request_id", "prev_hash", "hash",
+}
+
+// logWriter encodes log entries one at a time, so exports and archives are streamed.
+type logWriter interface {
+	Write(entry *entity.Log) error
+	Close() error
+}
+
+func newLogWriter(format string, w io.Writer) logWriter {
+	switch format {
+	case enums.LogFormatCSV:
+		return &csvLogWriter{writer: csv.NewWriter(w)}
+	case enums.LogFormatJSONL:
+		return &jsonLogWriter{w: w, encoder: json.NewEncoder(w)}
+	default:
+		return &jsonLogWriter{w: w, encoder: json.NewEncoder(w), array: true}
+	}
+}
+
+// jsonLogWriter writes a JSON array, or one JSON object per line for JSONL.
+type jsonLogWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+	array   bool
+	started bool
+}
+
+func (j *jsonLogWriter) Write(entry *entity.Log) error {
+	if j.array {
+		separator := ","
+		if !j.started {
+			separator = "["
+		}
+		if _, err := io.WriteString(j.w, separator); err != nil {
+			return err
+		}
+	}
+	j.started = true
+	return j.encoder.Encode(entry)
+}
+
+func (j *jsonLogWriter) Close() error {
+	if !j.array {
+		return nil
+	}
+	closing := "]\n"
+	if !j.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+type csvLogWriter struct {
+	writer  *csv.Writer
+	started bool
+}
+
+func (c *csvLogWriter) Write(entry *entity.Log) error {
+	if !c.started {
+		c.started = true
+		if err := c.writer.Write(logCSVHeader); err != nil {
+			return err
+		}
+	}
+	return c.writer.Write([]string{
+		strconv.FormatUint(entry.ID, 10),
+		entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		strconv.FormatUint(entry.UserID, 10),
+		entry.Action,
+		entry.Description,
+		entry.TargetType,
+		strconv.FormatUint(entry.TargetID, 10),
+		string(entry.Before),
+		string(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+		entry.PrevHash,
+		entry.Hash,
+	})
+}
+
+func (c *csvLogWriter) Close() error {
+	if !c.started {
+		c.started = true
+		if err := c.writer.Write(logCSVHeader); err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
@@ -1,26 +1,204 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
 	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/storage"
+	"venecraft-back/cmd/utils"
 )
 
+var ErrLogNotFound = errors.New("log entry not found")
+
+const (
+	// logCheckpointPrefix is where the signed checkpoints of the log are written in the storage.
+	logCheckpointPrefix = "audit/checkpoints/"
+
+	// logArchivePrefix is where the entries past their retention are archived in the storage.
+	logArchivePrefix = "audit/archives/"
+
+	// logExportBatchSize is how many entries an export reads per query.
+	logExportBatchSize = 500
+
+	// logVerifyBatchSize is how many entries the verification reads per query.
+	logVerifyBatchSize = 1000
+
+	// maxLogIntegrityProblems is how many problems a verification lists; the rest are only counted.
+	maxLogIntegrityProblems = 100
+
+	// logRetentionReactions is the LOG_RETENTION action standing for the entries of every reaction of
+	// the catalog, added or removed.
+	logRetentionReactions = "reactions"
+)
+
+// maxUserAgentLength is the longest user agent stored in an audit log entry, in characters.
+const maxUserAgentLength = 255
+
+// auditRedactedFields are removed from the snapshots stored in the audit log, compared in lower
+// case without underscores.
+var auditRedactedFields = map[string]bool{
+	"password":                    true,
+	"recoverpasswordtoken":        true,
+	"recoverpasswordtokenexpires": true,
+	"tokenhash":                   true,
+	"codehash":                    true,
+}
+
+// RequestInfo describes the HTTP request an action was performed with.
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// AuditActor is who performed an audited action. A zero UserID stands for the system, e.g. the
+// news scheduler.
+type AuditActor struct {
+	UserID uint64
+	RequestInfo
+}
+
+// LogPage is one page of the log.
+type LogPage struct {
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int64        `json:"total"`
+	HasMore  bool         `json:"has_more"`
+	Items    []entity.Log `json:"items"`
+}
+
+// LogCheckpoint pins the hash of the log at an entry, so rewriting or removing the end of the chain
+// is detected.
+type LogCheckpoint struct {
+	LastLogID uint64    `json:"last_log_id"`
+	LastHash  string    `json:"last_hash"`
+	CreatedAt time.Time `json:"created_at"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// payload returns the signed part of the checkpoint.
+func (c LogCheckpoint) payload() ([]byte, error) {
+	c.Signature = ""
+	return json.Marshal(c)
+}
+
+// LogArchiveDownload is an archive of the log with a temporary link to download it.
+type LogArchiveDownload struct {
+	entity.LogArchive
+	DownloadURL string `json:"download_url"`
+}
+
+// logArchivePayload returns the signed part of an archive record.
+func logArchivePayload(archive *entity.LogArchive) ([]byte, error) {
+	return json.Marshal([]interface{}{
+		archive.StorageKey, archive.Format, archive.Entries, archive.FirstLogID, archive.LastLogID, archive.SHA256, archive.Chain,
+	})
+}
+
+// LogIntegrityProblem is an inconsistency found while verifying the log.
+type LogIntegrityProblem struct {
+	Kind   string `json:"kind"`
+	LogID  uint64 `json:"log_id,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// LogVerification is the result of verifying the hash chain of the log against its checkpoints.
+type LogVerification struct {
+	Valid          bool                  `json:"valid"`
+	CheckedEntries int64                 `json:"checked_entries"`
+	LastLogID      uint64                `json:"last_log_id"`
+	LastHash       string                `json:"last_hash"`
+	Checkpoints    int                   `json:"checkpoints"`
+	Archives       int                   `json:"archives"`
+	ProblemCount   int                   `json:"problem_count"`
+	Problems       []LogIntegrityProblem `json:"problems"`
+}
+
+func (v *LogVerification) report(kind string, logID uint64, format string, args ...interface{}) {
+	v.ProblemCount++
+	if len(v.Problems) < maxLogIntegrityProblems {
+		v.Problems = append(v.Problems, LogIntegrityProblem{Kind: kind, LogID: logID, Detail: fmt.Sprintf(format, args...)})
+	}
+}
+
 type LogService interface {
 	CreateLog(log *entity.Log) error
+	Audit(actor AuditActor, action, targetType string, targetID uint64, before, after interface{})
 	GetLogByID(id uint64) (*entity.Log, error)
-	GetAllLogs() ([]entity.Log, error)
-	UpdateLog(log *entity.Log) error
-	DeleteLog(id uint64) error
+	GetLogs(filter repository.LogFilter, page, pageSize int) (*LogPage, error)
+	ExportLogs(filter repository.LogFilter, format string, w io.Writer) error
 	CountTransactions(fromDate time.Time) (int, error)
+	VerifyLogs(ctx context.Context) (*LogVerification, error)
+	WriteCheckpoint(ctx context.Context) (*LogCheckpoint, error)
+	ArchiveExpiredLogs(ctx context.Context) (int, error)
+	GetArchives(ctx context.Context) ([]LogArchiveDownload, error)
 }
 
 type logService struct {
-	logRepo repository.LogRepository
+	logRepo          repository.LogRepository
+	reactionTypeRepo repository.ReactionTypeRepository
+	storage          storage.Storage
+
+	retention        repository.LogRetention
+	archiveFormat    string
+	archiveBatchSize int
+	downloadTTL      time.Duration
 }
 
-func NewLogService(logRepo repository.LogRepository) LogService {
-	return &logService{logRepo: logRepo}
+func NewLogService(logRepo repository.LogRepository, reactionTypeRepo repository.ReactionTypeRepository, auditStorage storage.Storage) LogService {
+	archiveFormat := utils.GetEnv("LOG_ARCHIVE_FORMAT", enums.LogFormatJSONL)
+	if archiveFormat != enums.LogFormatJSONL && archiveFormat != enums.LogFormatCSV {
+		log.Printf("Invalid LOG_ARCHIVE_FORMAT %q, using %s", archiveFormat, enums.LogFormatJSONL)
+		archiveFormat = enums.LogFormatJSONL
+	}
+
+	return &logService{
+		logRepo:          logRepo,
+		reactionTypeRepo: reactionTypeRepo,
+		storage:          auditStorage,
+		retention: repository.LogRetention{
+			Actions: parseLogRetention(utils.GetEnv("LOG_RETENTION", "view_news=2160h,reactions=2160h")),
+			Default: utils.GetEnvDuration("LOG_RETENTION_DEFAULT", 0),
+		},
+		archiveFormat:    archiveFormat,
+		archiveBatchSize: utils.GetEnvInt("LOG_ARCHIVE_BATCH_SIZE", 10000),
+		downloadTTL:      utils.GetEnvDuration("LOG_ARCHIVE_DOWNLOAD_TTL", 15*time.Minute),
+	}
+}
+
+// parseLogRetention reads retention periods by action, written as "action=duration" pairs separated
+// by commas, e.g. "view_news=720h,like=720h". A zero duration keeps the action forever. The
+// "reactions" action stands for every reaction of the catalog.
+func parseLogRetention(value string) map[string]time.Duration {
+	retention := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		action, period, ok := strings.Cut(pair, "=")
+		duration, err := time.ParseDuration(strings.TrimSpace(period))
+		if !ok || err != nil || duration < 0 {
+			log.Printf("Invalid log retention %q, expected action=duration", pair)
+			continue
+		}
+		retention[strings.TrimSpace(action)] = duration
+	}
+	return retention
 }
 
 // CreateLog adds a new log entry
@@ -28,27 +206,449 @@ func (s *logService) CreateLog(log *entity.Log) error {
 	return s.logRepo.CreateLog(log)
 }
 
+// Audit records an administrative or moderation change with the state of the target before and
+// after it; either may be nil. The change already happened, so failures are logged rather than
+// returned.
+func (s *logService) Audit(actor AuditActor, action, targetType string, targetID uint64, before, after interface{}) {
+	entry := &entity.Log{
+		UserID:      actor.UserID,
+		Action:      action,
+		Description: auditDescription(actor.UserID, action, targetType, targetID),
+		TargetType:  targetType,
+		TargetID:    targetID,
+		IP:          actor.IP,
+		UserAgent:   truncateRunes(actor.UserAgent, maxUserAgentLength),
+		RequestID:   actor.RequestID,
+		Timestamp:   time.Now(),
+	}
+
+	var err error
+	if entry.Before, err = auditSnapshot(before); err == nil {
+		entry.After, err = auditSnapshot(after)
+	}
+	if err == nil {
+		err = s.logRepo.CreateLog(entry)
+	}
+	if err != nil {
+		log.Printf("Error recording audit log %s on %s %d: %v", action, targetType, targetID, err)
+	}
+}
+
 // GetLogByID retrieves a log entry by its ID
 func (s *logService) GetLogByID(id uint64) (*entity.Log, error) {
-	return s.logRepo.GetLogByID(id)
+	entry, err := s.logRepo.GetLogByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrLogNotFound
+	}
+	return entry, nil
 }
 
-// GetAllLogs retrieves all log entries
-func (s *logService) GetAllLogs() ([]entity.Log, error) {
-	return s.logRepo.GetAllLogs()
+// GetLogs returns a page of the log entries matching the filter, newest first.
+func (s *logService) GetLogs(filter repository.LogFilter, page, pageSize int) (*LogPage, error) {
+	logs, total, err := s.logRepo.GetLogPage(filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &LogPage{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+		Items:    logs,
+	}, nil
 }
 
-// UpdateLog updates an existing log entry
-func (s *logService) UpdateLog(log *entity.Log) error {
-	return s.logRepo.UpdateLog(log)
+// ExportLogs streams the entries matching the filter to w in chain order, as a JSON array, JSONL or
+// CSV.
+func (s *logService) ExportLogs(filter repository.LogFilter, format string, w io.Writer) error {
+	writer := newLogWriter(format, w)
+	err := s.logRepo.EachLog(filter, logExportBatchSize, func(logs []entity.Log) error {
+		for i := range logs {
+			if err := writer.Write(&logs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
 }
 
-// DeleteLog performs a logical delete on a log entry
-func (s *logService) DeleteLog(id uint64) error {
-	return s.logRepo.DeleteLog(id)
+// ArchiveExpiredLogs moves the entries past their retention to compressed files in the storage,
+// deleting them once their archive is recorded. It returns how many entries were archived.
+func (s *logService) ArchiveExpiredLogs(ctx context.Context) (int, error) {
+	retention, err := s.expandRetention()
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+		logs, err := s.logRepo.GetExpiredLogs(retention, time.Now(), s.archiveBatchSize)
+		if err != nil || len(logs) == 0 {
+			return archived, err
+		}
+		if err := s.archiveLogs(ctx, logs); err != nil {
+			return archived, err
+		}
+		archived += len(logs)
+	}
+}
+
+// expandRetention replaces the "reactions" retention with the actions the reactions of the catalog
+// are logged with, inactive ones included. Actions configured explicitly keep their own retention.
+func (s *logService) expandRetention() (repository.LogRetention, error) {
+	keep, ok := s.retention.Actions[logRetentionReactions]
+	if !ok {
+		return s.retention, nil
+	}
+	reactionTypes, err := s.reactionTypeRepo.GetReactionTypes(true)
+	if err != nil {
+		return repository.LogRetention{}, err
+	}
+
+	actions := maps.Clone(s.retention.Actions)
+	delete(actions, logRetentionReactions)
+	for _, reactionType := range reactionTypes {
+		for _, action := range reactionLogActions(reactionType.Key) {
+			if _, configured := s.retention.Actions[action]; !configured {
+				actions[action] = keep
+			}
+		}
+	}
+	return repository.LogRetention{Actions: actions, Default: s.retention.Default}, nil
+}
+
+func (s *logService) archiveLogs(ctx context.Context, logs []entity.Log) error {
+	var body bytes.Buffer
+	compressor := gzip.NewWriter(&body)
+	writer := newLogWriter(s.archiveFormat, compressor)
+	ids := make([]uint64, len(logs))
+	chain := make([]entity.LogArchiveEntry, len(logs))
+	for i := range logs {
+		if err := writer.Write(&logs[i]); err != nil {
+			return err
+		}
+		ids[i] = logs[i].ID
+		chain[i] = entity.LogArchiveEntry{ID: logs[i].ID, PrevHash: logs[i].PrevHash, Hash: logs[i].Hash}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+
+	first, last := logs[0], logs[len(logs)-1]
+	sum := sha256.Sum256(body.Bytes())
+	archive := &entity.LogArchive{
+		// The key only depends on the entries, so a retry after a failure replaces the same file.
+		StorageKey: fmt.Sprintf("%s%s/%020d-%020d.%s.gz", logArchivePrefix, first.Timestamp.UTC().Format("2006/01"), first.ID, last.ID, s.archiveFormat),
+		Format:     s.archiveFormat,
+		Entries:    len(logs),
+		FirstLogID: first.ID,
+		LastLogID:  last.ID,
+		From:       first.Timestamp,
+		To:         last.Timestamp,
+		SHA256:     hex.EncodeToString(sum[:]),
+		Chain:      chain,
+	}
+	for _, entry := range logs {
+		if entry.Timestamp.Before(archive.From) {
+			archive.From = entry.Timestamp
+		}
+		if entry.Timestamp.After(archive.To) {
+			archive.To = entry.Timestamp
+		}
+	}
+	payload, err := logArchivePayload(archive)
+	if err != nil {
+		return err
+	}
+	if archive.Signature, err = utils.SignLogCheckpoint(payload); err != nil {
+		return err
+	}
+
+	if err := s.storage.Put(ctx, archive.StorageKey, bytes.NewReader(body.Bytes()), int64(body.Len()), "application/gzip"); err != nil {
+		return err
+	}
+	return s.logRepo.ArchiveLogs(archive, ids)
+}
+
+// GetArchives lists the archives of the log, oldest first, with links to download them.
+func (s *logService) GetArchives(ctx context.Context) ([]LogArchiveDownload, error) {
+	archives, err := s.logRepo.GetLogArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	downloads := make([]LogArchiveDownload, 0, len(archives))
+	for _, archive := range archives {
+		url, err := s.storage.PresignGet(ctx, archive.StorageKey, s.downloadTTL)
+		if err != nil {
+			return nil, err
+		}
+		downloads = append(downloads, LogArchiveDownload{LogArchive: archive, DownloadURL: url})
+	}
+	return downloads, nil
 }
 
 // CountTransactions counts the number of logs with "transaction" action within a specified date range
 func (s *logService) CountTransactions(fromDate time.Time) (int, error) {
 	return s.logRepo.CountTransactions("transaction", fromDate)
 }
+
+// VerifyLogs walks the whole log checking that every entry matches its hash and follows the
+// previous one, and that the entries pinned by the checkpoints in the storage are still there.
+// Entries removed by the archival are accounted for by the chain of the signed archive records:
+// a gap is only accepted when every entry missing from it was archived.
+func (s *logService) VerifyLogs(ctx context.Context) (*LogVerification, error) {
+	result := &LogVerification{Problems: []LogIntegrityProblem{}}
+	checkpoints, err := s.loadCheckpoints(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Checkpoints = len(checkpoints)
+	archives, err := s.loadArchives(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Archives = len(archives)
+	archived := archivedEntries(archives)
+
+	prevHash := ""
+	var afterID uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		logs, err := s.logRepo.GetLogsAfter(afterID, logVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range logs {
+			entry := &logs[i]
+			if entry.PrevHash != prevHash && !archivedBetween(archived, prevHash, afterID, entry) {
+				result.report(enums.LogChainBroken, entry.ID, "expected previous hash %q, found %q", prevHash, entry.PrevHash)
+			}
+			if hash, err := repository.LogChainHash(entry); err != nil {
+				result.report(enums.LogEntryModified, entry.ID, "entry cannot be hashed: %v", err)
+			} else if hash != entry.Hash {
+				result.report(enums.LogEntryModified, entry.ID, "content hashes to %q, stored hash is %q", hash, entry.Hash)
+			}
+			if checkpoint, ok := checkpoints[entry.ID]; ok {
+				if checkpoint.LastHash != entry.Hash {
+					result.report(enums.LogCheckpointMismatch, entry.ID, "checkpoint of %s pinned hash %q, stored hash is %q",
+						checkpoint.CreatedAt.Format(time.RFC3339), checkpoint.LastHash, entry.Hash)
+				}
+				delete(checkpoints, entry.ID)
+			}
+
+			prevHash = entry.Hash
+			afterID = entry.ID
+			result.CheckedEntries++
+		}
+		if len(logs) < logVerifyBatchSize {
+			break
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(checkpoints)) {
+		if entry, ok := archived[checkpoints[id].LastHash]; ok && entry.ID == id {
+			continue
+		}
+		result.report(enums.LogCheckpointEntryMissing, id, "entry pinned by the checkpoint of %s is missing",
+			checkpoints[id].CreatedAt.Format(time.RFC3339))
+	}
+
+	result.LastLogID = afterID
+	result.LastHash = prevHash
+	result.Valid = result.ProblemCount == 0
+	return result, nil
+}
+
+// loadCheckpoints reads the checkpoints from the storage by the entry they pin. Unreadable or
+// wrongly signed checkpoints are reported and left out.
+func (s *logService) loadCheckpoints(ctx context.Context, result *LogVerification) (map[uint64]LogCheckpoint, error) {
+	objects, err := s.storage.List(ctx, logCheckpointPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[uint64]LogCheckpoint, len(objects))
+	for _, object := range objects {
+		checkpoint, err := s.readCheckpoint(ctx, object.Key)
+		if err != nil {
+			result.report(enums.LogCheckpointInvalid, 0, "checkpoint %s cannot be read: %v", object.Key, err)
+			continue
+		}
+		payload, err := checkpoint.payload()
+		valid, err := verifyLogSignature(payload, err, checkpoint.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			result.report(enums.LogCheckpointInvalid, checkpoint.LastLogID, "checkpoint %s has an invalid signature", object.Key)
+			continue
+		}
+		checkpoints[checkpoint.LastLogID] = *checkpoint
+	}
+	return checkpoints, nil
+}
+
+// loadArchives returns the archive records with a valid signature. The others are reported.
+func (s *logService) loadArchives(result *LogVerification) ([]entity.LogArchive, error) {
+	archives, err := s.logRepo.GetLogArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	signed := archives[:0]
+	for _, archive := range archives {
+		payload, err := logArchivePayload(&archive)
+		valid, err := verifyLogSignature(payload, err, archive.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			result.report(enums.LogArchiveInvalid, archive.FirstLogID, "archive %s has an invalid signature", archive.StorageKey)
+			continue
+		}
+		signed = append(signed, archive)
+	}
+	return signed, nil
+}
+
+// verifyLogSignature checks the signature of a checkpoint or archive record from its encoded
+// payload. A payload that could not be encoded is invalid; a missing secret is an error.
+func verifyLogSignature(payload []byte, encodeErr error, signature string) (bool, error) {
+	if encodeErr != nil {
+		return false, nil
+	}
+	return utils.VerifyLogCheckpoint(payload, signature)
+}
+
+// archivedEntries indexes the archived entries of the archives by their hash.
+func archivedEntries(archives []entity.LogArchive) map[string]entity.LogArchiveEntry {
+	archived := make(map[string]entity.LogArchiveEntry)
+	for _, archive := range archives {
+		for _, entry := range archive.Chain {
+			archived[entry.Hash] = entry
+		}
+	}
+	return archived
+}
+
+// archivedBetween reports whether the entries missing between the previous entry of the log, with
+// the given ID and hash, and the entry were all archived: following the previous hashes back from
+// the entry must only go through archived entries, with decreasing IDs between both, until it
+// reaches the previous entry.
+func archivedBetween(archived map[string]entity.LogArchiveEntry, prevHash string, prevID uint64, entry *entity.Log) bool {
+	hash, before := entry.PrevHash, entry.ID
+	for hash != prevHash {
+		archivedEntry, ok := archived[hash]
+		if !ok || archivedEntry.ID <= prevID || archivedEntry.ID >= before {
+			return false
+		}
+		hash, before = archivedEntry.PrevHash, archivedEntry.ID
+	}
+	return true
+}
+
+func (s *logService) readCheckpoint(ctx context.Context, key string) (*LogCheckpoint, error) {
+	reader, _, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var checkpoint LogCheckpoint
+	if err := json.NewDecoder(io.LimitReader(reader, 64<<10)).Decode(&checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// WriteCheckpoint signs the hash of the latest entry and writes it to the storage. Nothing is
+// written when the log is empty or the latest entry already has a checkpoint.
+func (s *logService) WriteCheckpoint(ctx context.Context) (*LogCheckpoint, error) {
+	last, err := s.logRepo.GetLastLog()
+	if err != nil || last == nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%020d.json", logCheckpointPrefix, last.ID)
+	if _, err := s.storage.Stat(ctx, key); err == nil {
+		return nil, nil
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	checkpoint := &LogCheckpoint{
+		LastLogID: last.ID,
+		LastHash:  last.Hash,
+		CreatedAt: time.Now().UTC(),
+	}
+	payload, err := checkpoint.payload()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Signature, err = utils.SignLogCheckpoint(payload); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, key, bytes.NewReader(body), int64(len(body)), "application/json"); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func auditDescription(actorID uint64, action, targetType string, targetID uint64) string {
+	actor := "System"
+	if actorID != 0 {
+		actor = fmt.Sprintf("User with id: %d", actorID)
+	}
+	return fmt.Sprintf("%s performed %s on %s with id: %d", actor, action, targetType, targetID)
+}
+
+// auditSnapshot encodes the state of a record as JSON without its secrets.
+func auditSnapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil {
+		return data, nil
+	}
+	for key := range fields {
+		if auditRedactedFields[strings.ReplaceAll(strings.ToLower(key), "_", "")] {
+			delete(fields, key)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
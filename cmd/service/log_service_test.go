@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogRepository keeps the log in memory. Methods the tests do not need panic through the nil
+// embedded interface.
+type fakeLogRepository struct {
+	repository.LogRepository
+	logs      []entity.Log
+	archives  []entity.LogArchive
+	retention repository.LogRetention
+}
+
+func (r *fakeLogRepository) GetLastLog() (*entity.Log, error) {
+	if len(r.logs) == 0 {
+		return nil, nil
+	}
+	last := r.logs[len(r.logs)-1]
+	return &last, nil
+}
+
+func (r *fakeLogRepository) GetLogsAfter(afterID uint64, limit int) ([]entity.Log, error) {
+	var logs []entity.Log
+	for _, log := range r.logs {
+		if log.ID > afterID && len(logs) < limit {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *fakeLogRepository) GetLogArchives() ([]entity.LogArchive, error) {
+	return append([]entity.LogArchive(nil), r.archives...), nil
+}
+
+// GetExpiredLogs returns the entries of the actions with a retention period, regardless of their age,
+// except the latest entry of the log.
+func (r *fakeLogRepository) GetExpiredLogs(retention repository.LogRetention, now time.Time, limit int) ([]entity.Log, error) {
+	r.retention = retention
+	var logs []entity.Log
+	for _, log := range r.logs[:len(r.logs)-1] {
+		if retention.Actions[log.Action] > 0 && len(logs) < limit {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *fakeLogRepository) ArchiveLogs(archive *entity.LogArchive, ids []uint64) error {
+	r.archives = append(r.archives, *archive)
+	r.remove(ids...)
+	return nil
+}
+
+// append chains a new entry to the log, like the repository does.
+func (r *fakeLogRepository) append(t *testing.T, action string) {
+	t.Helper()
+	entry := entity.Log{
+		ID:          uint64(len(r.logs) + 1),
+		UserID:      1,
+		Action:      action,
+		Description: fmt.Sprintf("entry %d", len(r.logs)+1),
+		Timestamp:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(len(r.logs)) * time.Hour),
+	}
+	if len(r.logs) > 0 {
+		entry.ID = r.logs[len(r.logs)-1].ID + 1
+		entry.PrevHash = r.logs[len(r.logs)-1].Hash
+	}
+	var err error
+	entry.Hash, err = repository.LogChainHash(&entry)
+	require.NoError(t, err)
+	r.logs = append(r.logs, entry)
+}
+
+func (r *fakeLogRepository) remove(ids ...uint64) {
+	kept := r.logs[:0]
+	for _, log := range r.logs {
+		removed := false
+		for _, id := range ids {
+			removed = removed || log.ID == id
+		}
+		if !removed {
+			kept = append(kept, log)
+		}
+	}
+	r.logs = kept
+}
+
+func newTestLogService(t *testing.T, entries int) (*logService, *fakeLogRepository) {
+	t.Helper()
+	t.Setenv("LOG_CHECKPOINT_SECRET", "test-secret")
+
+	auditStorage, err := storage.NewLocalStorage(storage.LocalConfig{Root: t.TempDir(), BaseURL: "http://localhost/audit-files"})
+	require.NoError(t, err)
+
+	repo := &fakeLogRepository{}
+	for i := 0; i < entries; i++ {
+		repo.append(t, "news_update")
+	}
+	return &logService{
+		logRepo:          repo,
+		storage:          auditStorage,
+		archiveFormat:    enums.LogFormatJSONL,
+		archiveBatchSize: 100,
+		downloadTTL:      time.Minute,
+	}, repo
+}
+
+func problemKinds(result *LogVerification) []string {
+	kinds := make([]string, 0, len(result.Problems))
+	for _, problem := range result.Problems {
+		kinds = append(kinds, problem.Kind)
+	}
+	return kinds
+}
+
+func TestVerifyLogsValidChain(t *testing.T) {
+	service, repo := newTestLogService(t, 5)
+
+	checkpoint, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, uint64(5), checkpoint.LastLogID)
+
+	// The latest entry already has a checkpoint.
+	again, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, again)
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid, problemKinds(result))
+	assert.Equal(t, int64(5), result.CheckedEntries)
+	assert.Equal(t, 1, result.Checkpoints)
+	assert.Equal(t, repo.logs[4].Hash, result.LastHash)
+}
+
+func TestVerifyLogsDetectsModifiedEntry(t *testing.T) {
+	service, repo := newTestLogService(t, 5)
+	repo.logs[2].Description = "rewritten"
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{enums.LogEntryModified}, problemKinds(result))
+	assert.Equal(t, uint64(3), result.Problems[0].LogID)
+}
+
+func TestVerifyLogsDetectsRehashedEntry(t *testing.T) {
+	service, repo := newTestLogService(t, 5)
+	repo.logs[2].Description = "rewritten"
+	repo.logs[2].Hash, _ = repository.LogChainHash(&repo.logs[2])
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{enums.LogChainBroken}, problemKinds(result))
+	assert.Equal(t, uint64(4), result.Problems[0].LogID)
+}
+
+func TestVerifyLogsDetectsDeletedEntry(t *testing.T) {
+	service, repo := newTestLogService(t, 5)
+	repo.remove(3)
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{enums.LogChainBroken}, problemKinds(result))
+	assert.Equal(t, uint64(4), result.Problems[0].LogID)
+}
+
+func TestVerifyLogsDetectsTruncatedLog(t *testing.T) {
+	service, repo := newTestLogService(t, 5)
+	_, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+	repo.logs = repo.logs[:3]
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{enums.LogCheckpointEntryMissing}, problemKinds(result))
+	assert.Equal(t, uint64(5), result.Problems[0].LogID)
+}
+
+func TestVerifyLogsDetectsForgedCheckpoint(t *testing.T) {
+	service, repo := newTestLogService(t, 3)
+	_, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+
+	t.Setenv("LOG_CHECKPOINT_SECRET", "attacker-secret")
+	repo.append(t, "news_update")
+	_, err = service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+	t.Setenv("LOG_CHECKPOINT_SECRET", "test-secret")
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{enums.LogCheckpointInvalid}, problemKinds(result))
+	assert.Equal(t, 1, result.Checkpoints)
+}
+
+func TestVerifyLogsRequiresSecret(t *testing.T) {
+	service, _ := newTestLogService(t, 3)
+	_, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+
+	t.Setenv("LOG_CHECKPOINT_SECRET", "")
+	_, err = service.VerifyLogs(context.Background())
+	assert.Error(t, err)
+}
+
+type fakeReactionTypeRepository struct {
+	repository.ReactionTypeRepository
+	reactionTypes   []entity.ReactionType
+	includeInactive bool
+}
+
+func (r *fakeReactionTypeRepository) GetReactionTypes(includeInactive bool) ([]entity.ReactionType, error) {
+	r.includeInactive = includeInactive
+	return r.reactionTypes, nil
+}
+
+// newArchivingLogService returns a log service whose log holds the given actions, in order, and
+// which archives the view_news entries.
+func newArchivingLogService(t *testing.T, actions ...string) (*logService, *fakeLogRepository) {
+	t.Helper()
+	service, repo := newTestLogService(t, 0)
+	for _, action := range actions {
+		repo.append(t, action)
+	}
+	service.retention = repository.LogRetention{Actions: map[string]time.Duration{"view_news": time.Hour}}
+	return service, repo
+}
+
+func TestVerifyLogsAcceptsArchivedEntries(t *testing.T) {
+	service, repo := newArchivingLogService(t, "news_update", "view_news", "view_news")
+	_, err := service.WriteCheckpoint(context.Background())
+	require.NoError(t, err)
+	repo.append(t, "news_update")
+	repo.append(t, "view_news")
+	repo.append(t, "news_update")
+
+	archived, err := service.ArchiveExpiredLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, archived)
+	require.Len(t, repo.archives, 1)
+	assert.Equal(t, uint64(2), repo.archives[0].FirstLogID)
+	assert.Equal(t, uint64(5), repo.archives[0].LastLogID)
+
+	objects, err := service.storage.List(context.Background(), logArchivePrefix)
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	// The checkpoint pins entry 3, which is now archived.
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid, problemKinds(result))
+	assert.Equal(t, int64(3), result.CheckedEntries)
+	assert.Equal(t, 1, result.Checkpoints)
+}
+
+func TestVerifyLogsDetectsDeletedEntryBetweenArchivedEntries(t *testing.T) {
+	service, repo := newArchivingLogService(t, "news_update", "view_news", "news_update", "view_news", "news_update")
+	_, err := service.ArchiveExpiredLogs(context.Background())
+	require.NoError(t, err)
+
+	// Entry 3 was not archived, it lies within the IDs of the archive.
+	repo.remove(3)
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{enums.LogChainBroken}, problemKinds(result))
+	assert.Equal(t, uint64(5), result.Problems[0].LogID)
+}
+
+func TestVerifyLogsDetectsForgedArchive(t *testing.T) {
+	service, repo := newArchivingLogService(t, "news_update", "view_news", "news_update")
+	_, err := service.ArchiveExpiredLogs(context.Background())
+	require.NoError(t, err)
+
+	// Hide the deletion of entry 3 behind the archive.
+	repo.archives[0].Chain = append(repo.archives[0].Chain, entity.LogArchiveEntry{ID: 3, PrevHash: repo.archives[0].Chain[0].Hash, Hash: repo.logs[1].PrevHash})
+
+	result, err := service.VerifyLogs(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, problemKinds(result), enums.LogArchiveInvalid)
+}
+
+func TestParseLogRetention(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]time.Duration
+	}{
+		{"empty", "", map[string]time.Duration{}},
+		{"single", "view_news=720h", map[string]time.Duration{"view_news": 720 * time.Hour}},
+		{"several with spaces", " view_news = 2h , reactions=30m,", map[string]time.Duration{"view_news": 2 * time.Hour, "reactions": 30 * time.Minute}},
+		{"zero keeps forever", "news_delete=0s", map[string]time.Duration{"news_delete": 0}},
+		{"invalid pairs skipped", "view_news,like=soon,dislike=-1h,unlike=1h", map[string]time.Duration{"unlike": time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseLogRetention(tt.value))
+		})
+	}
+}
+
+func TestExpandRetention(t *testing.T) {
+	reactionTypes := &fakeReactionTypeRepository{reactionTypes: []entity.ReactionType{
+		{Key: "like", Active: true},
+		{Key: "heart", Active: false},
+	}}
+	service := &logService{
+		reactionTypeRepo: reactionTypes,
+		retention: repository.LogRetention{
+			Actions: map[string]time.Duration{"view_news": time.Hour, "reactions": 2 * time.Hour, "unheart": 0},
+			Default: 3 * time.Hour,
+		},
+	}
+
+	retention, err := service.expandRetention()
+	require.NoError(t, err)
+	assert.True(t, reactionTypes.includeInactive)
+	assert.Equal(t, repository.LogRetention{
+		Actions: map[string]time.Duration{
+			"view_news": time.Hour,
+			"like":      2 * time.Hour,
+			"unlike":    2 * time.Hour,
+			"heart":     2 * time.Hour,
+			"unheart":   0,
+		},
+		Default: 3 * time.Hour,
+	}, retention)
+	assert.Contains(t, service.retention.Actions, "reactions")
+}
+
+func TestExpandRetentionWithoutReactions(t *testing.T) {
+	service := &logService{retention: repository.LogRetention{Actions: map[string]time.Duration{"view_news": time.Hour}}}
+
+	retention, err := service.expandRetention()
+	require.NoError(t, err)
+	assert.Equal(t, service.retention, retention)
+}
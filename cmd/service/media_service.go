@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/storage"
+)
+
+var (
+	ErrMediaNotFound = errors.New("media not found")
+	ErrMediaInUse    = errors.New("media is still referenced")
+	ErrMediaNotImage = errors.New("media is not an image")
+)
+
+// mediaPrefixes are the storage prefixes the garbage collector sweeps for orphaned objects.
+var mediaPrefixes = []string{"images/", "files/", "uploads/"}
+
+// mediaCollectBatch is the number of unreferenced media removed per garbage collection query.
+const mediaCollectBatch = 100
+
+// MediaItem is a media asset with its variants and the rows that use it.
+type MediaItem struct {
+	entity.MediaAsset
+	Variants       map[string]string           `json:"variants"`
+	ReferenceCount int64                       `json:"reference_count"`
+	References     []repository.MediaReference `json:"references,omitempty"`
+}
+
+// MediaPage is one page of the media library.
+type MediaPage struct {
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+	HasMore  bool        `json:"has_more"`
+	Items    []MediaItem `json:"items"`
+}
+
+type MediaService interface {
+	UploadImage(ctx context.Context, uploaderID uint64, file io.Reader) (*MediaItem, error)
+	CreateMedia(media *entity.MediaAsset) (*MediaItem, error)
+	GetMedia(id uint64) (*MediaItem, error)
+	GetImage(id uint64) (*MediaItem, error)
+	GetMediaPage(filter repository.MediaFilter, page, pageSize int) (*MediaPage, error)
+	DeleteMedia(ctx context.Context, id uint64) error
+	CollectGarbage(ctx context.Context) (int, error)
+}
+
+type mediaService struct {
+	mediaRepo    repository.MediaRepository
+	imageService ImageService
+	storage      storage.Storage
+
+	// gracePeriod protects recent uploads that are not attached to anything yet.
+	gracePeriod time.Duration
+}
+
+func NewMediaService(mediaRepo repository.MediaRepository, imageService ImageService, fileStorage storage.Storage, gracePeriod time.Duration) MediaService {
+	return &mediaService{
+		mediaRepo:    mediaRepo,
+		imageService: imageService,
+		storage:      fileStorage,
+		gracePeriod:  gracePeriod,
+	}
+}
+
+// UploadImage stores an image through the image pipeline and records it in the media library.
+// Uploading an image that is already in the library returns the existing asset.
+func (s *mediaService) UploadImage(ctx context.Context, uploaderID uint64, file io.Reader) (*MediaItem, error) {
+	image, err := s.imageService.UploadImage(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreateMedia(&entity.MediaAsset{
+		Key:         image.Key,
+		URL:         image.URL,
+		UploadedBy:  uploaderID,
+		ContentType: image.ContentType,
+		Size:        image.Size,
+		Width:       image.Width,
+		Height:      image.Height,
+		Hash:        image.Hash,
+	})
+}
+
+// CreateMedia records a stored file in the media library. When the file is already recorded,
+// the existing asset is returned instead.
+func (s *mediaService) CreateMedia(media *entity.MediaAsset) (*MediaItem, error) {
+	existing, err := s.mediaRepo.GetMediaByKey(media.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return s.GetMedia(existing.ID)
+	}
+
+	if err := s.mediaRepo.CreateMedia(media); err != nil {
+		// A concurrent upload of the same file may have created it first.
+		existing, findErr := s.mediaRepo.GetMediaByKey(media.Key)
+		if findErr != nil || existing == nil {
+			return nil, err
+		}
+		media = existing
+	}
+	return s.GetMedia(media.ID)
+}
+
+func (s *mediaService) GetMedia(id uint64) (*MediaItem, error) {
+	media, err := s.mediaRepo.GetMediaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if media == nil {
+		return nil, ErrMediaNotFound
+	}
+
+	references, err := s.mediaRepo.GetReferences(media.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MediaItem{
+		MediaAsset:     *media,
+		Variants:       s.variantURLs(media),
+		ReferenceCount: int64(len(references)),
+		References:     references,
+	}, nil
+}
+
+// GetImage returns a media asset that can be used as an image of news, servers or profiles.
+func (s *mediaService) GetImage(id uint64) (*MediaItem, error) {
+	media, err := s.GetMedia(id)
+	if err != nil {
+		return nil, err
+	}
+	if !isImageType(media.ContentType) {
+		return nil, ErrMediaNotImage
+	}
+	return media, nil
+}
+
+func (s *mediaService) GetMediaPage(filter repository.MediaFilter, page, pageSize int) (*MediaPage, error) {
+	media, total, err := s.mediaRepo.GetMediaPage(filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(media))
+	for _, asset := range media {
+		urls = append(urls, asset.URL)
+	}
+	counts, err := s.mediaRepo.CountReferences(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]MediaItem, 0, len(media))
+	for _, asset := range media {
+		items = append(items, MediaItem{
+			MediaAsset:     asset,
+			Variants:       s.variantURLs(&asset),
+			ReferenceCount: counts[asset.URL],
+		})
+	}
+
+	return &MediaPage{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+		Items:    items,
+	}, nil
+}
+
+// variantURLs returns the resized variants of image assets; other files have none.
+func (s *mediaService) variantURLs(media *entity.MediaAsset) map[string]string {
+	if !isImageType(media.ContentType) {
+		return nil
+	}
+	return s.imageService.VariantURLs(media.URL)
+}
+
+// DeleteMedia removes an asset that nothing references anymore, along with its stored files.
+func (s *mediaService) DeleteMedia(ctx context.Context, id uint64) error {
+	item, err := s.GetMedia(id)
+	if err != nil {
+		return err
+	}
+	if item.ReferenceCount > 0 {
+		return ErrMediaInUse
+	}
+	return s.deleteMedia(ctx, &item.MediaAsset)
+}
+
+func (s *mediaService) deleteMedia(ctx context.Context, media *entity.MediaAsset) error {
+	if err := s.imageService.DeleteImage(ctx, &StoredImage{Key: media.Key}); err != nil {
+		return err
+	}
+	return s.mediaRepo.DeleteMedia(media.ID)
+}
+
+// CollectGarbage deletes the media and stored files that are no longer referenced by any news,
+// news revision, server or user profile, nor embedded in the content of news, translations or
+// revisions, once the grace period has passed. Files without a media
+// entry, such as uploads made before the media library existed, are collected too.
+func (s *mediaService) CollectGarbage(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.gracePeriod)
+	deleted := 0
+
+	for {
+		media, err := s.mediaRepo.GetUnreferencedMedia(cutoff, mediaCollectBatch)
+		if err != nil {
+			return deleted, err
+		}
+		for i := range media {
+			if err := s.deleteMedia(ctx, &media[i]); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(media) < mediaCollectBatch {
+			break
+		}
+	}
+
+	for _, prefix := range mediaPrefixes {
+		orphans, err := s.collectOrphans(ctx, prefix, cutoff)
+		deleted += orphans
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// collectOrphans deletes the stored files under the prefix that have no media entry and are not
+// referenced by URL.
+func (s *mediaService) collectOrphans(ctx context.Context, prefix string, cutoff time.Time) (int, error) {
+	objects, err := s.storage.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	// Variants belong to their original image, so files are grouped by the original key.
+	groups := make(map[string][]storage.ObjectInfo)
+	for _, object := range objects {
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+		originalKey := s.imageService.OriginalKey(object.Key)
+		groups[originalKey] = append(groups[originalKey], object)
+	}
+	if len(groups) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(groups))
+	urls := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+		urls = append(urls, s.storage.URL(key))
+	}
+	tracked, err := s.mediaRepo.GetExistingKeys(keys)
+	if err != nil {
+		return 0, err
+	}
+	references, err := s.mediaRepo.CountReferences(urls)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for key, group := range groups {
+		if tracked[key] || references[s.storage.URL(key)] > 0 {
+			continue
+		}
+		for _, object := range group {
+			if err := s.storage.Delete(ctx, object.Key); err != nil {
+				return deleted, err
+			}
+			log.Printf("Deleted orphaned storage object %s", object.Key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// MediaGarbageCollector periodically removes media that is no longer referenced and upload slots
+// that were never confirmed.
+type MediaGarbageCollector struct {
+	mediaService  MediaService
+	uploadService UploadService
+	interval      time.Duration
+}
+
+func NewMediaGarbageCollector(mediaService MediaService, uploadService UploadService, interval time.Duration) *MediaGarbageCollector {
+	return &MediaGarbageCollector{mediaService: mediaService, uploadService: uploadService, interval: interval}
+}
+
+// Start runs the garbage collector in the background until the context is cancelled.
+func (c *MediaGarbageCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *MediaGarbageCollector) run(ctx context.Context) {
+	expired, err := c.uploadService.DeleteExpiredUploads()
+	if err != nil {
+		log.Printf("Error deleting expired uploads: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("Removed %d expired upload slots", expired)
+	}
+
+	deleted, err := c.mediaService.CollectGarbage(ctx)
+	if err != nil {
+		log.Printf("Error collecting unreferenced media: %v", err)
+	}
+	if deleted > 0 {
+		log.Printf("Removed %d unreferenced media items", deleted)
+	}
+}
+
+func isImageType(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/metrics"
+	"venecraft-back/cmd/repository"
+)
+
+// subscriberCounter reports how many clients are connected to the event stream.
+type subscriberCounter interface {
+	Subscribers() int
+}
+
+// MetricsService reads the application gauges exported on /metrics.
+type MetricsService interface {
+	AppStats() (*metrics.AppStats, error)
+}
+
+type metricsService struct {
+	userRepo     repository.UserRepository
+	registerRepo repository.RegisterRepository
+	outboxRepo   repository.OutboxRepository
+	subscribers  subscriberCounter
+}
+
+func NewMetricsService(userRepo repository.UserRepository, registerRepo repository.RegisterRepository, outboxRepo repository.OutboxRepository, subscribers subscriberCounter) MetricsService {
+	return &metricsService{
+		userRepo:     userRepo,
+		registerRepo: registerRepo,
+		outboxRepo:   outboxRepo,
+		subscribers:  subscribers,
+	}
+}
+
+func (s *metricsService) AppStats() (*metrics.AppStats, error) {
+	emails, err := s.outboxRepo.CountEmailsByStatus()
+	if err != nil {
+		return nil, err
+	}
+	// Every status is exported, so an empty outbox reads as zero rather than as a missing series.
+	for _, status := range []string{enums.EmailStatusPending, enums.EmailStatusSending, enums.EmailStatusSent, enums.EmailStatusDead} {
+		if _, ok := emails[status]; !ok {
+			emails[status] = 0
+		}
+	}
+
+	activeUsers, err := s.userRepo.CountActiveUsers()
+	if err != nil {
+		return nil, err
+	}
+	awaitingApproval, awaitingVerification, err := s.registerRepo.CountPendingRegisters()
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.AppStats{
+		OutboxEmails:                     emails,
+		ActiveUsers:                      int64(activeUsers),
+		RegistrationsPendingApproval:     awaitingApproval,
+		RegistrationsPendingVerification: awaitingVerification,
+		EventSubscribers:                 int64(s.subscribers.Subscribers()),
+	}, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"venecraft-back/cmd/repository"
+)
+
+// maxAnalyticsRange is the longest period the analytics can be requested for.
+const maxAnalyticsRange = 366 * 24 * time.Hour
+
+// DailyCount is one point of an engagement trend.
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// EngagementTotals sums up the engagement over the requested period.
+type EngagementTotals struct {
+	Views           int64            `json:"views"`
+	UniqueViewers   int64            `json:"unique_viewers"`
+	Reactions       int64            `json:"reactions"`
+	ReactionsByType map[string]int64 `json:"reactions_by_type"`
+	Comments        int64            `json:"comments"`
+}
+
+// EngagementTrends holds one point per day of the requested period.
+type EngagementTrends struct {
+	Views           []DailyCount            `json:"views"`
+	Reactions       []DailyCount            `json:"reactions"`
+	ReactionsByType map[string][]DailyCount `json:"reactions_by_type"`
+	Comments        []DailyCount            `json:"comments"`
+}
+
+// NewsAnalytics is the engagement of a single news.
+type NewsAnalytics struct {
+	NewsID uint64           `json:"news_id"`
+	Title  string           `json:"title"`
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"`
+	Totals EngagementTotals `json:"totals"`
+	Trends EngagementTrends `json:"trends"`
+}
+
+// NewsAnalyticsOverview is the engagement across all news, with a breakdown per news.
+type NewsAnalyticsOverview struct {
+	From   time.Time                      `json:"from"`
+	To     time.Time                      `json:"to"`
+	Totals EngagementTotals               `json:"totals"`
+	Trends EngagementTrends               `json:"trends"`
+	Posts  []repository.NewsEngagementRow `json:"posts"`
+}
+
+type NewsAnalyticsService interface {
+	GetNewsAnalytics(newsID uint64, from, to time.Time) (*NewsAnalytics, error)
+	GetAnalyticsOverview(from, to time.Time, limit int) (*NewsAnalyticsOverview, error)
+}
+
+type newsAnalyticsService struct {
+	analyticsRepo repository.NewsAnalyticsRepository
+	newsRepo      repository.NewsRepository
+}
+
+func NewNewsAnalyticsService(analyticsRepo repository.NewsAnalyticsRepository, newsRepo repository.NewsRepository) NewsAnalyticsService {
+	return &newsAnalyticsService{analyticsRepo, newsRepo}
+}
+
+func (s *newsAnalyticsService) GetNewsAnalytics(newsID uint64, from, to time.Time) (*NewsAnalytics, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+
+	news, err := s.newsRepo.GetNewsByID(newsID, true)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
+
+	scope := repository.AnalyticsScope{NewsID: &newsID, From: from, To: to}
+	totals, trends, err := s.engagement(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NewsAnalytics{
+		NewsID: news.ID,
+		Title:  news.Title,
+		From:   from,
+		To:     to,
+		Totals: *totals,
+		Trends: *trends,
+	}, nil
+}
+
+func (s *newsAnalyticsService) GetAnalyticsOverview(from, to time.Time, limit int) (*NewsAnalyticsOverview, error) {
+	if err := validateAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+
+	scope := repository.AnalyticsScope{From: from, To: to}
+	totals, trends, err := s.engagement(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.analyticsRepo.GetNewsEngagement(scope, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NewsAnalyticsOverview{
+		From:   from,
+		To:     to,
+		Totals: *totals,
+		Trends: *trends,
+		Posts:  posts,
+	}, nil
+}
+
+// engagement gathers the totals and daily trends of views, reactions and comments in the scope.
+func (s *newsAnalyticsService) engagement(scope repository.AnalyticsScope) (*EngagementTotals, *EngagementTrends, error) {
+	views, uniqueViewers, err := s.analyticsRepo.CountViews(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	viewTrend, err := s.analyticsRepo.GetViewTrend(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	reactionTrend, err := s.analyticsRepo.GetReactionTrend(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	commentTrend, err := s.analyticsRepo.GetCommentTrend(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totals := &EngagementTotals{
+		Views:           views,
+		UniqueViewers:   uniqueViewers,
+		ReactionsByType: make(map[string]int64),
+	}
+	reactionsByDay := make([]repository.DailyCount, 0, len(reactionTrend))
+	reactionsByType := make(map[string][]repository.DailyCount)
+	for _, point := range reactionTrend {
+		totals.Reactions += point.Count
+		totals.ReactionsByType[point.Type] += point.Count
+		reactionsByDay = append(reactionsByDay, repository.DailyCount{Day: point.Day, Count: point.Count})
+		reactionsByType[point.Type] = append(reactionsByType[point.Type], repository.DailyCount{Day: point.Day, Count: point.Count})
+	}
+	for _, point := range commentTrend {
+		totals.Comments += point.Count
+	}
+
+	trends := &EngagementTrends{
+		Views:           dailySeries(viewTrend, scope.From, scope.To),
+		Reactions:       dailySeries(reactionsByDay, scope.From, scope.To),
+		ReactionsByType: make(map[string][]DailyCount, len(reactionsByType)),
+		Comments:        dailySeries(commentTrend, scope.From, scope.To),
+	}
+	for reactionType, points := range reactionsByType {
+		trends.ReactionsByType[reactionType] = dailySeries(points, scope.From, scope.To)
+	}
+	return totals, trends, nil
+}
+
+// dailySeries sums the counts per UTC day and fills the days without activity with zeros.
+func dailySeries(points []repository.DailyCount, from, to time.Time) []DailyCount {
+	counts := make(map[string]int64, len(points))
+	for _, point := range points {
+		counts[point.Day.Format(time.DateOnly)] += point.Count
+	}
+
+	var series []DailyCount
+	last := to.UTC().Format(time.DateOnly)
+	for day := from.UTC().Truncate(24 * time.Hour); ; day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		series = append(series, DailyCount{Date: date, Count: counts[date]})
+		if date >= last {
+			break
+		}
+	}
+	return series
+}
+
+func validateAnalyticsRange(from, to time.Time) error {
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxAnalyticsRange {
+		return fmt.Errorf("the period cannot exceed %d days", int(maxAnalyticsRange.Hours()/24))
+	}
+	return nil
+}
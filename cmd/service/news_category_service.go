@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+type NewsCategoryService interface {
+	CreateCategory(category *entity.NewsCategory) error
+	GetAllCategories() ([]entity.NewsCategory, error)
+	GetCategoryByID(id uint64) (*entity.NewsCategory, error)
+	UpdateCategory(id uint64, category *entity.NewsCategory) (*entity.NewsCategory, error)
+	DeleteCategory(id uint64) error
+}
+
+type newsCategoryService struct {
+	categoryRepo repository.NewsCategoryRepository
+}
+
+func NewNewsCategoryService(categoryRepo repository.NewsCategoryRepository) NewsCategoryService {
+	return &newsCategoryService{categoryRepo}
+}
+
+func (s *newsCategoryService) CreateCategory(category *entity.NewsCategory) error {
+	if err := s.prepareCategory(category, 0); err != nil {
+		return err
+	}
+	return s.categoryRepo.CreateCategory(category)
+}
+
+func (s *newsCategoryService) GetAllCategories() ([]entity.NewsCategory, error) {
+	return s.categoryRepo.GetAllCategories()
+}
+
+func (s *newsCategoryService) GetCategoryByID(id uint64) (*entity.NewsCategory, error) {
+	category, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, errors.New("news category not found")
+	}
+	return category, nil
+}
+
+func (s *newsCategoryService) UpdateCategory(id uint64, update *entity.NewsCategory) (*entity.NewsCategory, error) {
+	category, err := s.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != "" {
+		category.Name = update.Name
+	}
+	if update.Slug != "" {
+		category.Slug = update.Slug
+	}
+	if update.Description != "" {
+		category.Description = update.Description
+	}
+
+	if err := s.prepareCategory(category, id); err != nil {
+		return nil, err
+	}
+	if err := s.categoryRepo.UpdateCategory(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *newsCategoryService) DeleteCategory(id uint64) error {
+	if _, err := s.GetCategoryByID(id); err != nil {
+		return err
+	}
+	return s.categoryRepo.DeleteCategory(id)
+}
+
+// prepareCategory validates the name and makes sure the slug is well formed and unique.
+func (s *newsCategoryService) prepareCategory(category *entity.NewsCategory, currentID uint64) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return errors.New("the category name must be provided")
+	}
+
+	if category.Slug == "" {
+		category.Slug = category.Name
+	}
+	category.Slug = utils.Slugify(category.Slug)
+	if category.Slug == "" {
+		return errors.New("invalid category slug")
+	}
+
+	existing, err := s.categoryRepo.GetCategoryBySlug(category.Slug)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != currentID {
+		return errors.New("a category with this slug already exists")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"venecraft-back/cmd/entity"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FieldChange records the previous and new value of a single field.
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RevisionChanges is the diff stored with each news revision.
+type RevisionChanges struct {
+	Title    *FieldChange `json:"title,omitempty"`
+	ImageURL *FieldChange `json:"image_url,omitempty"`
+
+	// Content is a unified diff of the Markdown source
+	Content string `json:"content,omitempty"`
+}
+
+// buildRevisionChanges diffs the revisioned fields of a news and reports whether any changed.
+func buildRevisionChanges(previous, updated *entity.News) (string, bool, error) {
+	var changes RevisionChanges
+	if previous.Title != updated.Title {
+		changes.Title = &FieldChange{From: previous.Title, To: updated.Title}
+	}
+	if previous.ImageURL != updated.ImageURL {
+		changes.ImageURL = &FieldChange{From: previous.ImageURL, To: updated.ImageURL}
+	}
+	if previous.Content != updated.Content {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(ensureTrailingNewline(previous.Content)),
+			B:        difflib.SplitLines(ensureTrailingNewline(updated.Content)),
+			FromFile: "previous",
+			ToFile:   "current",
+			Context:  3,
+		})
+		if err != nil {
+			return "", false, err
+		}
+		changes.Content = diff
+	}
+
+	if changes.Title == nil && changes.ImageURL == nil && changes.Content == "" {
+		return "", false, nil
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return "", false, err
+	}
+	return string(encoded), true, nil
+}
+
+func ensureTrailingNewline(text string) string {
+	if strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
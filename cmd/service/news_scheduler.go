@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// NewsScheduler periodically publishes scheduled news once their publish time is reached.
+type NewsScheduler struct {
+	newsService NewsService
+	interval    time.Duration
+}
+
+func NewNewsScheduler(newsService NewsService, interval time.Duration) *NewsScheduler {
+	return &NewsScheduler{newsService: newsService, interval: interval}
+}
+
+// Start runs the scheduler in the background until the context is cancelled.
+func (s *NewsScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *NewsScheduler) run() {
+	published, err := s.newsService.PublishDueNews()
+	if err != nil {
+		log.Printf("Error publishing scheduled news: %v", err)
+	}
+	if published > 0 {
+		log.Printf("Published %d scheduled news", published)
+	}
+}
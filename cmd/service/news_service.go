@@ -3,231 +3,780 @@ package service
 import (
 	"errors"
 	"fmt"
+	"html"
+	"log"
+	"strings"
 	"time"
+	"venecraft-back/cmd/email"
 	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/events"
 	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
 )
 
+// NewsViewer describes who is reading the news and what they are allowed to see.
+type NewsViewer struct {
+	UserID uint64
+
+	// IncludeUnpublished exposes drafts, scheduled, archived and expired news (admins only).
+	IncludeUnpublished bool
+
+	// Locale is the locale explicitly requested, taking precedence over the user's preference.
+	Locale string
+
+	// AcceptLanguage is the raw Accept-Language header, used when no other preference matches.
+	AcceptLanguage string
+}
+
+// NewsListFilter holds the optional filters accepted by the news listings.
+type NewsListFilter struct {
+	// Category is the slug of the category to filter by
+	Category string
+
+	// Tag is the name of the tag to filter by
+	Tag string
+}
+
+// NewsSearchResult is one page of full-text search results.
+type NewsSearchResult struct {
+	Query    string                   `json:"query"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+	Total    int64                    `json:"total"`
+	HasMore  bool                     `json:"has_more"`
+	Results  []map[string]interface{} `json:"results"`
+}
+
+var (
+	ErrNewsNotFound     = errors.New("news not found")
+	ErrRevisionNotFound = errors.New("news revision not found")
+)
+
+// NewsUpdate lists the changes to apply to a news. Nil fields are left untouched.
+type NewsUpdate struct {
+	Title    *string
+	Content  *string
+	ImageURL *string
+	Status   *string
+
+	PublishAt      *time.Time
+	ClearPublishAt bool
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool
+
+	// CategoryID moves the news to another category; zero removes the category.
+	CategoryID *uint64
+	Tags       *[]string
+	Pinned     *bool
+
+	// RestoredFrom marks the update as a rollback to the given revision.
+	RestoredFrom *uint64
+
+	// IncludeUnpublished lets the update reach news players cannot see. Only admins can.
+	IncludeUnpublished bool
+}
+
+// maxTagLength is the longest tag name accepted, matching the tags column size.
+const maxTagLength = 50
+
 type NewsService interface {
-	CreateNews(news *entity.News) error
-	GetAllNews(userID uint64) ([]map[string]interface{}, error)
-	GetLatestNews(userID uint64) ([]map[string]interface{}, error)
-	GetNewsByID(userID uint64, id uint64) (map[string]interface{}, error)
-	UpdateNews(news *entity.News) error
-	DeleteNews(id uint64) error
-	ToggleReactionNews(userID, newsID uint64, reactionType string) (bool, error)
+	CreateNews(actor AuditActor, news *entity.News) error
+	GetAllNews(viewer NewsViewer, filter NewsListFilter) ([]map[string]interface{}, error)
+	GetLatestNews(viewer NewsViewer, filter NewsListFilter) ([]map[string]interface{}, error)
+	GetNewsByID(viewer NewsViewer, id uint64) (map[string]interface{}, error)
+	UpdateNews(actor AuditActor, id uint64, update NewsUpdate) (*entity.News, error)
+	GetNewsRevisions(newsID uint64) ([]entity.NewsRevision, error)
+	GetNewsRevision(newsID, revisionID uint64) (*entity.NewsRevision, error)
+	RestoreNewsRevision(actor AuditActor, newsID, revisionID uint64) (*entity.News, error)
+	DeleteNews(actor AuditActor, id uint64) error
+	PublishNews(actor AuditActor, id uint64) error
+	ArchiveNews(actor AuditActor, id uint64) error
+	PublishDueNews() (int, error)
+	SearchNews(viewer NewsViewer, query string, page, pageSize int) (*NewsSearchResult, error)
 }
 
 type newsService struct {
-	newsRepo     repository.NewsRepository
-	reactionRepo repository.ReactionRepository
-	logRepo      repository.LogRepository
+	newsRepo        repository.NewsRepository
+	categoryRepo    repository.NewsCategoryRepository
+	reactionService ReactionService
+	commentRepo     repository.CommentRepository
+	revisionRepo    repository.NewsRevisionRepository
+	translationRepo repository.NewsTranslationRepository
+	analyticsRepo   repository.NewsAnalyticsRepository
+	imageService    ImageService
+	logService      LogService
+	userRepo        repository.UserRepository
+	outboxRepo      repository.OutboxRepository
+	settingService  SettingService
+	notifier        NotificationService
+	publisher       events.Publisher
+	templates       *email.Templates
+	senders         email.Senders
+
+	// searchLanguage is the PostgreSQL text search configuration used for full-text search.
+	searchLanguage string
 }
 
-func NewNewsService(newsRepo repository.NewsRepository, reactionRepo repository.ReactionRepository, logRepo repository.LogRepository) NewsService {
-	return &newsService{newsRepo, reactionRepo, logRepo}
+func NewNewsService(newsRepo repository.NewsRepository, categoryRepo repository.NewsCategoryRepository, reactionService ReactionService, commentRepo repository.CommentRepository, revisionRepo repository.NewsRevisionRepository, translationRepo repository.NewsTranslationRepository, analyticsRepo repository.NewsAnalyticsRepository, imageService ImageService, logService LogService, userRepo repository.UserRepository, outboxRepo repository.OutboxRepository, settingService SettingService, notifier NotificationService, publisher events.Publisher, templates *email.Templates) NewsService {
+	return &newsService{
+		newsRepo:        newsRepo,
+		categoryRepo:    categoryRepo,
+		reactionService: reactionService,
+		commentRepo:     commentRepo,
+		revisionRepo:    revisionRepo,
+		translationRepo: translationRepo,
+		analyticsRepo:   analyticsRepo,
+		imageService:    imageService,
+		logService:      logService,
+		userRepo:        userRepo,
+		outboxRepo:      outboxRepo,
+		settingService:  settingService,
+		notifier:        notifier,
+		publisher:       publisher,
+		templates:       templates,
+		senders:         email.LoadSenders(),
+
+		searchLanguage: utils.GetEnv("NEWS_SEARCH_LANGUAGE", "spanish"),
+	}
 }
 
-func (s *newsService) CreateNews(news *entity.News) error {
+func (s *newsService) CreateNews(actor AuditActor, news *entity.News) error {
 	if news.Title == "" || news.Content == "" {
 		return errors.New("the title and content must be provided")
 	}
-	return s.newsRepo.CreateNews(news)
+	if err := applyNewsLifecycle(news, time.Now()); err != nil {
+		return err
+	}
+	if news.Locale == "" {
+		news.Locale = utils.DefaultLocale()
+	}
+	news.Locale = utils.NormalizeLocale(news.Locale)
+	if !utils.IsSupportedLocale(news.Locale) {
+		return ErrUnsupportedLocale
+	}
+	if err := renderNewsContent(news); err != nil {
+		return err
+	}
+	if err := s.resolveNewsTaxonomy(news); err != nil {
+		return err
+	}
+
+	if err := s.newsRepo.CreateNews(news); err != nil {
+		return err
+	}
+	s.logService.Audit(actor, enums.AuditNewsCreate, enums.AuditTargetNews, news.ID, nil, news)
+
+	if news.Status == enums.NewsStatusPublished {
+		go s.notifyNewsPublished(*news)
+	}
+	return nil
 }
 
-func (s *newsService) GetAllNews(userID uint64) ([]map[string]interface{}, error) {
-	newsList, err := s.newsRepo.GetAllNews()
+func (s *newsService) GetAllNews(viewer NewsViewer, filter NewsListFilter) ([]map[string]interface{}, error) {
+	newsList, err := s.newsRepo.GetAllNews(newsRepositoryFilter(viewer, filter))
 	if err != nil {
 		return nil, err
 	}
+	return s.buildNewsListResponse(viewer, newsList)
+}
 
-	var response []map[string]interface{}
-	for _, news := range newsList {
-		likeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "like")
-		if err != nil {
-			return nil, err
-		}
+func (s *newsService) GetLatestNews(viewer NewsViewer, filter NewsListFilter) ([]map[string]interface{}, error) {
+	newsList, err := s.newsRepo.GetLatestNews(newsRepositoryFilter(viewer, filter))
+	if err != nil {
+		return nil, err
+	}
+	return s.buildNewsListResponse(viewer, newsList)
+}
 
-		dislikeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "dislike")
-		if err != nil {
-			return nil, err
-		}
+func (s *newsService) GetNewsByID(viewer NewsViewer, newsID uint64) (map[string]interface{}, error) {
+	news, err := s.newsRepo.GetNewsByID(newsID, viewer.IncludeUnpublished)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, nil
+	}
 
-		userLiked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "like")
-		if err != nil {
-			return nil, err
+	if viewer.UserID != 0 {
+		if err := s.recordNewsView(viewer.UserID, news.ID); err != nil {
+			log.Printf("Failed to record view of news %d: %v", news.ID, err)
 		}
+	}
 
-		userDisliked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "dislike")
-		if err != nil {
-			return nil, err
-		}
+	data, err := s.prepareNewsResponse(viewer, []entity.News{*news})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildNewsResponse(data, news)
+}
 
-		newsData := map[string]interface{}{
-			"id":            news.ID,
-			"title":         news.Title,
-			"content":       news.Content,
-			"created_by":    news.CreatedBy,
-			"created_at":    news.CreatedAt,
-			"image_url":     news.ImageURL,
-			"like_count":    likeCount,
-			"dislike_count": dislikeCount,
-			"user_liked":    userLiked,
-			"user_disliked": userDisliked,
-		}
+// recordNewsView counts a read of the news, once per user and day, logging the first one.
+func (s *newsService) recordNewsView(userID, newsID uint64) error {
+	now := time.Now()
+	view := entity.NewsView{
+		NewsID:   newsID,
+		UserID:   userID,
+		ViewDate: now.UTC().Truncate(24 * time.Hour),
+		ViewedAt: now,
+	}
+	recorded, err := s.analyticsRepo.RecordView(&view)
+	if err != nil || !recorded {
+		return err
+	}
 
-		response = append(response, newsData)
+	logEntry := entity.Log{
+		UserID:      userID,
+		Action:      "view_news",
+		Description: fmt.Sprintf("User with id: %d viewed the news post with id: %d", userID, newsID),
+		Timestamp:   now,
 	}
+	return s.logService.CreateLog(&logEntry)
+}
 
-	return response, nil
+// UpdateNews applies a partial update to a news and records a revision when the title, content or
+// image changed.
+func (s *newsService) UpdateNews(actor AuditActor, id uint64, update NewsUpdate) (*entity.News, error) {
+	return s.updateNews(actor, enums.AuditNewsUpdate, id, update)
 }
 
-func (s *newsService) GetLatestNews(userID uint64) ([]map[string]interface{}, error) {
-	newsList, err := s.newsRepo.GetLatestNews()
+func (s *newsService) updateNews(actor AuditActor, action string, id uint64, update NewsUpdate) (*entity.News, error) {
+	news, err := s.newsRepo.GetNewsByID(id, update.IncludeUnpublished)
 	if err != nil {
 		return nil, err
 	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
+	previous := *news
 
-	var response []map[string]interface{}
-	for _, news := range newsList {
-		likeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "like")
-		if err != nil {
-			return nil, err
-		}
-
-		dislikeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "dislike")
-		if err != nil {
-			return nil, err
-		}
+	if update.Title != nil {
+		news.Title = strings.TrimSpace(*update.Title)
+	}
+	if update.Content != nil {
+		news.Content = *update.Content
+	}
+	if update.ImageURL != nil {
+		news.ImageURL = *update.ImageURL
+	}
+	if news.Title == "" || news.Content == "" {
+		return nil, errors.New("the title and content must be provided")
+	}
 
-		userLiked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "like")
-		if err != nil {
-			return nil, err
+	if update.Status != nil {
+		news.Status = *update.Status
+	}
+	if update.ClearPublishAt {
+		news.PublishAt = nil
+	} else if update.PublishAt != nil {
+		news.PublishAt = update.PublishAt
+	}
+	if update.ClearExpiresAt {
+		news.ExpiresAt = nil
+	} else if update.ExpiresAt != nil {
+		news.ExpiresAt = update.ExpiresAt
+	}
+	if update.CategoryID != nil {
+		news.CategoryID = update.CategoryID
+		if *update.CategoryID == 0 {
+			news.CategoryID = nil
 		}
-
-		userDisliked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "dislike")
-		if err != nil {
-			return nil, err
+	}
+	if update.Tags != nil {
+		news.Tags = make([]*entity.Tag, 0, len(*update.Tags))
+		for _, name := range *update.Tags {
+			news.Tags = append(news.Tags, &entity.Tag{Name: name})
 		}
+	}
+	if update.Pinned != nil {
+		news.Pinned = *update.Pinned
+	}
 
-		newsData := map[string]interface{}{
-			"id":            news.ID,
-			"title":         news.Title,
-			"content":       news.Content,
-			"created_by":    news.CreatedBy,
-			"created_at":    news.CreatedAt,
-			"image_url":     news.ImageURL,
-			"like_count":    likeCount,
-			"dislike_count": dislikeCount,
-			"user_liked":    userLiked,
-			"user_disliked": userDisliked,
-		}
+	if err := applyNewsLifecycle(news, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := renderNewsContent(news); err != nil {
+		return nil, err
+	}
+	if err := s.resolveNewsTaxonomy(news); err != nil {
+		return nil, err
+	}
 
-		response = append(response, newsData)
+	revisions, err := s.revisionsForUpdate(actor.UserID, &previous, news, update.RestoredFrom)
+	if err != nil {
+		return nil, err
 	}
+	if err := s.newsRepo.UpdateNewsWithRevisions(news, revisions...); err != nil {
+		return nil, err
+	}
+	s.logService.Audit(actor, action, enums.AuditTargetNews, news.ID, &previous, news)
 
-	return response, nil
+	if previous.Status != enums.NewsStatusPublished && news.Status == enums.NewsStatusPublished {
+		go s.notifyNewsPublished(*news)
+	}
+	return news, nil
 }
 
-func (s *newsService) GetNewsByID(userID, newsID uint64) (map[string]interface{}, error) {
-	news, err := s.newsRepo.GetNewsByID(newsID)
+// GetNewsRevisions lists the revisions of a news, newest first.
+func (s *newsService) GetNewsRevisions(newsID uint64) ([]entity.NewsRevision, error) {
+	news, err := s.newsRepo.GetNewsByID(newsID, true)
 	if err != nil {
 		return nil, err
 	}
 	if news == nil {
-		return nil, errors.New("news not found")
+		return nil, ErrNewsNotFound
 	}
+	return s.revisionRepo.GetRevisionsByNews(newsID)
+}
 
-	likeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "like")
+func (s *newsService) GetNewsRevision(newsID, revisionID uint64) (*entity.NewsRevision, error) {
+	revision, err := s.revisionRepo.GetRevision(newsID, revisionID)
 	if err != nil {
 		return nil, err
 	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
 
-	dislikeCount, err := s.reactionRepo.GetReactionCountForNews(news.ID, "dislike")
+// RestoreNewsRevision brings back the title, content and image of an older revision. The rollback
+// itself is stored as a new revision, so it can be undone as well.
+func (s *newsService) RestoreNewsRevision(actor AuditActor, newsID, revisionID uint64) (*entity.News, error) {
+	revision, err := s.GetNewsRevision(newsID, revisionID)
 	if err != nil {
 		return nil, err
 	}
 
-	userLiked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "like")
+	return s.updateNews(actor, enums.AuditNewsRestore, newsID, NewsUpdate{
+		Title:              &revision.Title,
+		Content:            &revision.Content,
+		ImageURL:           &revision.ImageURL,
+		RestoredFrom:       &revision.ID,
+		IncludeUnpublished: true,
+	})
+}
+
+// revisionsForUpdate returns the revisions to store for an update. News created before revisions
+// existed get a baseline revision with their previous state first.
+func (s *newsService) revisionsForUpdate(editorID uint64, previous, updated *entity.News, restoredFrom *uint64) ([]*entity.NewsRevision, error) {
+	changes, changed, err := buildRevisionChanges(previous, updated)
 	if err != nil {
 		return nil, err
 	}
+	if !changed {
+		return nil, nil
+	}
 
-	userDisliked, err := s.reactionRepo.HasUserReacted(userID, news.ID, "dislike")
+	var revisions []*entity.NewsRevision
+	latest, err := s.revisionRepo.GetLatestRevision(previous.ID)
 	if err != nil {
 		return nil, err
 	}
+	if latest == nil {
+		revisions = append(revisions, &entity.NewsRevision{
+			EditedBy:  previous.CreatedBy,
+			Title:     previous.Title,
+			Content:   previous.Content,
+			ImageURL:  previous.ImageURL,
+			CreatedAt: previous.CreatedAt,
+		})
+	}
 
-	newsData := map[string]interface{}{
-		"id":            news.ID,
-		"title":         news.Title,
-		"content":       news.Content,
-		"created_by":    news.CreatedBy,
-		"created_at":    news.CreatedAt,
-		"image_url":     news.ImageURL,
-		"like_count":    likeCount,
-		"dislike_count": dislikeCount,
-		"user_liked":    userLiked,
-		"user_disliked": userDisliked,
+	revisions = append(revisions, &entity.NewsRevision{
+		EditedBy:     editorID,
+		Title:        updated.Title,
+		Content:      updated.Content,
+		ImageURL:     updated.ImageURL,
+		Changes:      changes,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	})
+	return revisions, nil
+}
+
+func (s *newsService) DeleteNews(actor AuditActor, id uint64) error {
+	news, err := s.newsRepo.GetNewsByID(id, true)
+	if err != nil {
+		return err
+	}
+	if news == nil {
+		return ErrNewsNotFound
 	}
 
-	return newsData, nil
+	if err := s.newsRepo.DeleteNews(id); err != nil {
+		return err
+	}
+	s.logService.Audit(actor, enums.AuditNewsDelete, enums.AuditTargetNews, id, news, nil)
+	return nil
 }
 
-func (s *newsService) UpdateNews(news *entity.News) error {
-	fmt.Print(news)
-	fmt.Print(news.ID)
-	return s.newsRepo.UpdateNews(news)
+// PublishNews makes a draft or scheduled news visible immediately.
+func (s *newsService) PublishNews(actor AuditActor, id uint64) error {
+	news, err := s.newsRepo.GetNewsByID(id, true)
+	if err != nil {
+		return err
+	}
+	if news == nil {
+		return ErrNewsNotFound
+	}
+	previous := *news
+
+	now := time.Now()
+	published, err := s.newsRepo.MarkNewsPublished(id, now)
+	if err != nil {
+		return err
+	}
+	if published {
+		news.Status = enums.NewsStatusPublished
+		news.PublishAt = &now
+		s.logService.Audit(actor, enums.AuditNewsPublish, enums.AuditTargetNews, id, &previous, news)
+		go s.notifyNewsPublished(*news)
+	}
+	return nil
 }
 
-func (s *newsService) DeleteNews(id uint64) error {
-	return s.newsRepo.DeleteNews(id)
+// ArchiveNews hides a news article from players without deleting it.
+func (s *newsService) ArchiveNews(actor AuditActor, id uint64) error {
+	news, err := s.newsRepo.GetNewsByID(id, true)
+	if err != nil {
+		return err
+	}
+	if news == nil {
+		return ErrNewsNotFound
+	}
+	previous := *news
+
+	news.Status = enums.NewsStatusArchived
+	if err := s.newsRepo.UpdateNews(news); err != nil {
+		return err
+	}
+	s.logService.Audit(actor, enums.AuditNewsArchive, enums.AuditTargetNews, id, &previous, news)
+	return nil
 }
 
-func (s *newsService) ToggleReactionNews(userID, newsID uint64, reactionType string) (bool, error) {
-	reacted, err := s.reactionRepo.HasUserReacted(userID, newsID, reactionType)
+// PublishDueNews publishes every scheduled news whose publish time has passed and
+// notifies players about them. It returns how many news were published.
+func (s *newsService) PublishDueNews() (int, error) {
+	now := time.Now()
+	dueNews, err := s.newsRepo.GetDueScheduledNews(now)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	if reacted {
-		err := s.reactionRepo.DeleteReaction(userID, newsID, reactionType)
+	published := 0
+	for _, news := range dueNews {
+		ok, err := s.newsRepo.MarkNewsPublished(news.ID, *news.PublishAt)
 		if err != nil {
-			return false, err
+			return published, err
+		}
+		if !ok {
+			continue
 		}
+		published++
+		previous := news
+		news.Status = enums.NewsStatusPublished
+		s.logService.Audit(AuditActor{}, enums.AuditNewsPublish, enums.AuditTargetNews, news.ID, &previous, &news)
+		s.notifyNewsPublished(news)
+	}
+
+	return published, nil
+}
+
+// SearchNews looks up news by title and content, returning ranked results with highlighted matches.
+func (s *newsService) SearchNews(viewer NewsViewer, query string, page, pageSize int) (*NewsSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("a search query must be provided")
+	}
+
+	hits, total, err := s.newsRepo.SearchNews(s.searchLanguage, query, viewer.IncludeUnpublished, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, map[string]interface{}{
+			"id":              hit.ID,
+			"title":           hit.Title,
+			"title_highlight": highlightMatches(hit.TitleHighlight),
+			"snippet":         highlightMatches(hit.Snippet),
+			"excerpt":         hit.Excerpt,
+			"image_url":       hit.ImageURL,
+			"created_at":      hit.CreatedAt,
+			"publish_at":      hit.PublishAt,
+			"status":          hit.Status,
+			"rank":            hit.Rank,
+		})
+	}
 
-		logEntry := entity.Log{
-			UserID:      userID,
-			Action:      "un" + reactionType,
-			Description: fmt.Sprintf("User with id: %d un%sd the news post with id: %d", userID, reactionType, newsID),
-			Timestamp:   time.Now(),
+	return &NewsSearchResult{
+		Query:    query,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+		Results:  results,
+	}, nil
+}
+
+// highlightMatches escapes a search fragment and turns the match markers into <mark> tags.
+func highlightMatches(fragment string) string {
+	escaped := html.EscapeString(fragment)
+	escaped = strings.ReplaceAll(escaped, repository.HighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, repository.HighlightStop, "</mark>")
+}
+
+// applyNewsLifecycle validates the status, publish and expiry dates of a news and
+// normalizes them so that the scheduler and the read paths agree on its visibility.
+func applyNewsLifecycle(news *entity.News, now time.Time) error {
+	if news.Status == "" {
+		news.Status = enums.NewsStatusPublished
+		if news.PublishAt != nil && news.PublishAt.After(now) {
+			news.Status = enums.NewsStatusScheduled
+		}
+	}
+	if !enums.IsValidNewsStatus(news.Status) {
+		return fmt.Errorf("invalid news status: %s", news.Status)
+	}
+
+	switch news.Status {
+	case enums.NewsStatusScheduled:
+		if news.PublishAt == nil {
+			return errors.New("publish_at is required for scheduled news")
 		}
-		if logErr := s.logRepo.CreateLog(&logEntry); logErr != nil {
-			return false, logErr
+	case enums.NewsStatusPublished:
+		if news.PublishAt == nil {
+			news.PublishAt = &now
+		} else if news.PublishAt.After(now) {
+			news.Status = enums.NewsStatusScheduled
 		}
+	}
 
-		return false, nil
+	if news.ExpiresAt != nil && news.PublishAt != nil && !news.ExpiresAt.After(*news.PublishAt) {
+		return errors.New("expires_at must be after publish_at")
 	}
+	return nil
+}
 
-	reaction := entity.Reaction{
-		UserID:    userID,
-		NewsID:    newsID,
-		Type:      reactionType,
-		Timestamp: time.Now(),
+// resolveNewsTaxonomy checks that the category exists and replaces the requested tag
+// names with persisted tags.
+func (s *newsService) resolveNewsTaxonomy(news *entity.News) error {
+	if news.CategoryID != nil {
+		category, err := s.categoryRepo.GetCategoryByID(*news.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return errors.New("news category not found")
+		}
 	}
-	if err := s.reactionRepo.CreateReaction(&reaction); err != nil {
-		return false, err
+	news.Category = nil
+
+	names := make([]string, 0, len(news.Tags))
+	for _, tag := range news.Tags {
+		if tag != nil {
+			names = append(names, tag.Name)
+		}
 	}
+	tags, err := s.newsRepo.GetOrCreateTags(utils.NormalizeTags(names, maxTagLength))
+	if err != nil {
+		return err
+	}
+	news.Tags = tags
+	return nil
+}
 
-	logEntry := entity.Log{
-		UserID:      userID,
-		Action:      reactionType,
-		Description: fmt.Sprintf("User with id: %d %sd the news post with id: %d", userID, reactionType, newsID),
-		Timestamp:   time.Now(),
+func newsRepositoryFilter(viewer NewsViewer, filter NewsListFilter) repository.NewsFilter {
+	return repository.NewsFilter{
+		IncludeUnpublished: viewer.IncludeUnpublished,
+		CategorySlug:       filter.Category,
+		Tag:                strings.ToLower(strings.TrimSpace(filter.Tag)),
+	}
+}
+
+// renderNewsContent renders the Markdown content to sanitized HTML and refreshes the excerpt.
+func renderNewsContent(news *entity.News) error {
+	contentHTML, err := utils.RenderMarkdown(news.Content)
+	if err != nil {
+		return fmt.Errorf("failed to render news content: %v", err)
 	}
-	if logErr := s.logRepo.CreateLog(&logEntry); logErr != nil {
-		return false, logErr
+	news.ContentHTML = contentHTML
+	news.Excerpt = utils.GenerateExcerpt(contentHTML, utils.ExcerptLength)
+	return nil
+}
+
+func (s *newsService) buildNewsListResponse(viewer NewsViewer, newsList []entity.News) ([]map[string]interface{}, error) {
+	data, err := s.prepareNewsResponse(viewer, newsList)
+	if err != nil {
+		return nil, err
 	}
 
-	return true, nil
+	var response []map[string]interface{}
+	for i := range newsList {
+		newsData, err := s.buildNewsResponse(data, &newsList[i])
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, newsData)
+	}
+	return response, nil
+}
+
+// newsResponseData holds what is loaded once per request for every news in a response.
+type newsResponseData struct {
+	localization  *newsLocalization
+	reactions     map[uint64]*ReactionSummary
+	commentCounts map[uint64]int64
+}
+
+func (s *newsService) prepareNewsResponse(viewer NewsViewer, newsList []entity.News) (*newsResponseData, error) {
+	localization, err := s.localize(viewer, newsList)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(newsList))
+	for _, news := range newsList {
+		ids = append(ids, news.ID)
+	}
+	reactions, err := s.reactionService.SummarizeReactions(viewer.UserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	commentCounts, err := s.commentRepo.CountVisibleCommentsByNews(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newsResponseData{localization: localization, reactions: reactions, commentCounts: commentCounts}, nil
+}
+
+func (s *newsService) buildNewsResponse(data *newsResponseData, news *entity.News) (map[string]interface{}, error) {
+	// News created before Markdown support have no rendered form stored yet.
+	if news.ContentHTML == "" && news.Content != "" {
+		if err := renderNewsContent(news); err != nil {
+			return nil, err
+		}
+	}
+
+	reactions := data.reactions[news.ID]
+	if reactions == nil {
+		reactions = &ReactionSummary{Counts: []ReactionCount{}, UserReactions: []string{}}
+	}
+
+	localized := data.localization.resolve(news)
+
+	newsData := map[string]interface{}{
+		"id":                news.ID,
+		"title":             localized.Title,
+		"content":           localized.Content,
+		"content_html":      localized.ContentHTML,
+		"excerpt":           localized.Excerpt,
+		"locale":            localized.Locale,
+		"available_locales": data.localization.availableLocales(news),
+		"created_by":        news.CreatedBy,
+		"created_at":        news.CreatedAt,
+		"image_url":         news.ImageURL,
+		"image_variants":    s.imageService.VariantURLs(news.ImageURL),
+		"status":            news.Status,
+		"category":          news.Category,
+		"tags":              tagNames(news.Tags),
+		"pinned":            news.Pinned,
+		"publish_at":        news.PublishAt,
+		"expires_at":        news.ExpiresAt,
+		"reactions":         reactions.Counts,
+		"user_reactions":    reactions.UserReactions,
+		"like_count":        reactions.Count("like"),
+		"dislike_count":     reactions.Count("dislike"),
+		"user_liked":        reactions.HasReacted("like"),
+		"user_disliked":     reactions.HasReacted("dislike"),
+		"comment_count":     data.commentCounts[news.ID],
+	}
+
+	return newsData, nil
+}
+
+func tagNames(tags []*entity.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// notifyNewsPublished announces a freshly published news article on the event stream and notifies
+// every active user that wants it, in their locale. Each user gets their own email with a link to
+// unsubscribe.
+func (s *newsService) notifyNewsPublished(news entity.News) {
+	s.publisher.Publish(enums.TopicNewsPublished, map[string]interface{}{
+		"news_id":   news.ID,
+		"title":     news.Title,
+		"locale":    news.Locale,
+		"image_url": news.ImageURL,
+	})
+
+	users, err := s.userRepo.GetActiveUsers()
+	if err != nil {
+		log.Printf("Error fetching users to notify about news %d: %v", news.ID, err)
+		return
+	}
+	users, err = s.settingService.FilterUsers(enums.SettingNewsPublished, users)
+	if err != nil {
+		log.Printf("Error fetching notification settings for news %d: %v", news.ID, err)
+		return
+	}
+
+	localization, err := s.localize(NewsViewer{}, []entity.News{news})
+	if err != nil {
+		log.Printf("Error loading translations of news %d: %v", news.ID, err)
+	}
+	titles := make(map[string]string)
+	usersByLocale := make(map[string][]entity.User)
+
+	emails := make([]entity.OutboxEmail, 0, len(users))
+	for _, user := range users {
+		locale := utils.NegotiateLocale(user.Locale)
+		title, ok := titles[locale]
+		if !ok {
+			title = news.Title
+			if localization != nil {
+				localization.locale = locale
+				title = localization.resolve(&news).Title
+			}
+			titles[locale] = title
+		}
+		usersByLocale[locale] = append(usersByLocale[locale], user)
+
+		unsubscribe, err := s.settingService.UnsubscribeLinks(user.ID, enums.SettingNewsPublished)
+		if err != nil {
+			log.Printf("Error signing news %d publication email: %v", news.ID, err)
+			return
+		}
+		outboxEmail, err := renderOutboxEmail(s.templates, "news/news_published", locale, map[string]interface{}{
+			"Title":          title,
+			"ImageURL":       news.ImageURL,
+			"UnsubscribeURL": unsubscribe.URL,
+		}, email.Message{
+			From:    s.senders.News,
+			To:      []string{user.Email},
+			Headers: unsubscribe.Headers,
+		})
+		if err != nil {
+			log.Printf("Error rendering news %d publication email: %v", news.ID, err)
+			return
+		}
+		emails = append(emails, *outboxEmail)
+	}
+	if err := s.outboxRepo.EnqueueEmails(emails); err != nil {
+		log.Printf("Error queueing news %d publication emails: %v", news.ID, err)
+	}
+
+	for locale, localeUsers := range usersByLocale {
+		s.notifier.NotifyUsers(localeUsers, enums.NotificationNewsPublished, map[string]interface{}{
+			"news_id": news.ID,
+			"title":   titles[locale],
+		})
+	}
 }
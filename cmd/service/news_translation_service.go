@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("news translation not found")
+	ErrUnsupportedLocale   = errors.New("unsupported locale")
+)
+
+type NewsTranslationService interface {
+	GetTranslations(newsID uint64) ([]entity.NewsTranslation, error)
+	SaveTranslation(editorID, newsID uint64, locale, title, content string) (*entity.NewsTranslation, error)
+	DeleteTranslation(newsID uint64, locale string) error
+}
+
+type newsTranslationService struct {
+	newsRepo        repository.NewsRepository
+	translationRepo repository.NewsTranslationRepository
+}
+
+func NewNewsTranslationService(newsRepo repository.NewsRepository, translationRepo repository.NewsTranslationRepository) NewsTranslationService {
+	return &newsTranslationService{newsRepo, translationRepo}
+}
+
+func (s *newsTranslationService) GetTranslations(newsID uint64) ([]entity.NewsTranslation, error) {
+	if _, err := s.getNews(newsID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.GetTranslationsByNewsIDs([]uint64{newsID})
+}
+
+// SaveTranslation creates or replaces the translation of a news for a locale other than its own.
+func (s *newsTranslationService) SaveTranslation(editorID, newsID uint64, locale, title, content string) (*entity.NewsTranslation, error) {
+	locale = utils.NormalizeLocale(locale)
+	if !utils.IsSupportedLocale(locale) {
+		return nil, ErrUnsupportedLocale
+	}
+
+	news, err := s.getNews(newsID)
+	if err != nil {
+		return nil, err
+	}
+	if locale == newsLocale(news) {
+		return nil, errors.New("the news is already written in this locale, update the news instead")
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" || strings.TrimSpace(content) == "" {
+		return nil, errors.New("the title and content must be provided")
+	}
+
+	contentHTML, err := utils.RenderMarkdown(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render translation content: %v", err)
+	}
+
+	translation := entity.NewsTranslation{
+		NewsID:      newsID,
+		Locale:      locale,
+		Title:       title,
+		Content:     content,
+		ContentHTML: contentHTML,
+		Excerpt:     utils.GenerateExcerpt(contentHTML, utils.ExcerptLength),
+		UpdatedBy:   editorID,
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.translationRepo.SaveTranslation(&translation); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.GetTranslation(newsID, locale)
+}
+
+func (s *newsTranslationService) DeleteTranslation(newsID uint64, locale string) error {
+	locale = utils.NormalizeLocale(locale)
+	if _, err := s.getNews(newsID); err != nil {
+		return err
+	}
+
+	translation, err := s.translationRepo.GetTranslation(newsID, locale)
+	if err != nil {
+		return err
+	}
+	if translation == nil {
+		return ErrTranslationNotFound
+	}
+	return s.translationRepo.DeleteTranslation(newsID, locale)
+}
+
+func (s *newsTranslationService) getNews(newsID uint64) (*entity.News, error) {
+	news, err := s.newsRepo.GetNewsByID(newsID, true)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
+	return news, nil
+}
+
+// newsLocale is the locale the news was written in; news created before translations
+// existed are assumed to be in the default locale.
+func newsLocale(news *entity.News) string {
+	if news.Locale == "" {
+		return utils.DefaultLocale()
+	}
+	return news.Locale
+}
+
+// newsLocalization holds the locale negotiated for a request and the translations of the
+// news being served, indexed by news ID and locale.
+type newsLocalization struct {
+	locale       string
+	translations map[uint64]map[string]*entity.NewsTranslation
+}
+
+// localizedNews is the title and content of a news in the locale it is served in.
+type localizedNews struct {
+	Locale      string
+	Title       string
+	Content     string
+	ContentHTML string
+	Excerpt     string
+}
+
+// resolve picks the translation for the negotiated locale, then the default locale, and
+// finally falls back to the original text of the news.
+func (l *newsLocalization) resolve(news *entity.News) localizedNews {
+	original := localizedNews{
+		Locale:      newsLocale(news),
+		Title:       news.Title,
+		Content:     news.Content,
+		ContentHTML: news.ContentHTML,
+		Excerpt:     news.Excerpt,
+	}
+	if l == nil || original.Locale == l.locale {
+		return original
+	}
+
+	translations := l.translations[news.ID]
+	for _, locale := range []string{l.locale, utils.DefaultLocale()} {
+		if translation, ok := translations[locale]; ok {
+			return localizedNews{
+				Locale:      translation.Locale,
+				Title:       translation.Title,
+				Content:     translation.Content,
+				ContentHTML: translation.ContentHTML,
+				Excerpt:     translation.Excerpt,
+			}
+		}
+		if locale == original.Locale {
+			break
+		}
+	}
+	return original
+}
+
+// availableLocales lists the locales the news can be read in.
+func (l *newsLocalization) availableLocales(news *entity.News) []string {
+	locales := []string{newsLocale(news)}
+	if l == nil {
+		return locales
+	}
+	for _, locale := range utils.SupportedLocales() {
+		if _, ok := l.translations[news.ID][locale]; ok && locale != locales[0] {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// localize negotiates the locale for the viewer and loads the translations of the given news.
+// The priority is an explicit locale, then the user's preference, then Accept-Language.
+func (s *newsService) localize(viewer NewsViewer, newsList []entity.News) (*newsLocalization, error) {
+	preferences := []string{viewer.Locale}
+	if viewer.UserID != 0 {
+		if user, err := s.userRepo.GetUserByID(viewer.UserID); err == nil && user != nil {
+			preferences = append(preferences, user.Locale)
+		}
+	}
+	preferences = append(preferences, viewer.AcceptLanguage)
+
+	localization := &newsLocalization{
+		locale:       utils.NegotiateLocale(preferences...),
+		translations: make(map[uint64]map[string]*entity.NewsTranslation),
+	}
+
+	ids := make([]uint64, 0, len(newsList))
+	for _, news := range newsList {
+		ids = append(ids, news.ID)
+	}
+	translations, err := s.translationRepo.GetTranslationsByNewsIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range translations {
+		translation := &translations[i]
+		if localization.translations[translation.NewsID] == nil {
+			localization.translations[translation.NewsID] = make(map[string]*entity.NewsTranslation)
+		}
+		localization.translations[translation.NewsID][translation.Locale] = translation
+	}
+	return localization, nil
+}
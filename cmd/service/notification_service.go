@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"text/template"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/events"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationPage is one page of a user's notifications.
+type NotificationPage struct {
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+	Total    int64                 `json:"total"`
+	HasMore  bool                  `json:"has_more"`
+	Unread   int64                 `json:"unread"`
+	Items    []entity.Notification `json:"items"`
+}
+
+// notificationSettings maps each notification type to the setting users turn it off with.
+var notificationSettings = map[string]string{
+	enums.NotificationRegistrationApproved: enums.SettingRegistrationStatus,
+	enums.NotificationNewsPublished:        enums.SettingNewsPublished,
+	enums.NotificationPasswordChanged:      enums.SettingSecurityAlerts,
+	enums.NotificationCommentHidden:        enums.SettingModerationActions,
+	enums.NotificationCommentRestored:      enums.SettingModerationActions,
+	enums.NotificationCommentDeleted:       enums.SettingModerationActions,
+	enums.NotificationReportResolved:       enums.SettingModerationActions,
+}
+
+// notificationMessage is the title and body of a notification type, as templates over its data.
+type notificationMessage struct {
+	title string
+	body  string
+}
+
+// notificationMessages holds the text of each notification type by locale. The notification
+// is stored in the user's locale, falling back to the default locale and then to English.
+var notificationMessages = map[string]map[string]notificationMessage{
+	"en": {
+		enums.NotificationRegistrationApproved: {"Welcome to Venecraft", "Your registration request was approved. See you in game!"},
+		enums.NotificationNewsPublished:        {"New announcement", "{{.title}}"},
+		enums.NotificationPasswordChanged:      {"Your password was changed", "If you didn't change it, reset your password and contact support."},
+		enums.NotificationCommentHidden:        {"Your comment was hidden", "A moderator hid your comment{{if .reason}}: {{.reason}}{{end}}."},
+		enums.NotificationCommentRestored:      {"Your comment was restored", "A moderator made your comment visible again."},
+		enums.NotificationCommentDeleted:       {"Your comment was removed", "A moderator removed your comment."},
+		enums.NotificationReportResolved:       {"Your report was reviewed", "{{if .dismissed}}A moderator reviewed the comment you reported and kept it.{{else}}A moderator reviewed the comment you reported and took action.{{end}}"},
+	},
+	"es": {
+		enums.NotificationRegistrationApproved: {"Bienvenido a Venecraft", "Tu solicitud de registro fue aprobada. ¡Nos vemos en el juego!"},
+		enums.NotificationNewsPublished:        {"Nuevo anuncio", "{{.title}}"},
+		enums.NotificationPasswordChanged:      {"Tu contraseña ha cambiado", "Si no fuiste tú, restablece tu contraseña y contacta con soporte."},
+		enums.NotificationCommentHidden:        {"Tu comentario fue ocultado", "Un moderador ocultó tu comentario{{if .reason}}: {{.reason}}{{end}}."},
+		enums.NotificationCommentRestored:      {"Tu comentario fue restaurado", "Un moderador volvió a mostrar tu comentario."},
+		enums.NotificationCommentDeleted:       {"Tu comentario fue eliminado", "Un moderador eliminó tu comentario."},
+		enums.NotificationReportResolved:       {"Tu reporte fue revisado", "{{if .dismissed}}Un moderador revisó el comentario que reportaste y lo mantuvo.{{else}}Un moderador revisó el comentario que reportaste y tomó medidas.{{end}}"},
+	},
+}
+
+type NotificationService interface {
+	GetNotifications(userID uint64, unreadOnly bool, page, pageSize int) (*NotificationPage, error)
+	CountUnread(userID uint64) (int64, error)
+	MarkRead(userID, id uint64) error
+	MarkAllRead(userID uint64) (int64, error)
+	NotifyUser(userID uint64, notificationType string, data map[string]interface{})
+	NotifyUsers(users []entity.User, notificationType string, data map[string]interface{})
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	settingService   SettingService
+	publisher        events.Publisher
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, settingService SettingService, publisher events.Publisher) NotificationService {
+	return &notificationService{notificationRepo, userRepo, settingService, publisher}
+}
+
+func (s *notificationService) GetNotifications(userID uint64, unreadOnly bool, page, pageSize int) (*NotificationPage, error) {
+	notifications, total, err := s.notificationRepo.GetNotificationPage(userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationPage{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+		Unread:   unread,
+		Items:    notifications,
+	}, nil
+}
+
+func (s *notificationService) CountUnread(userID uint64) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+func (s *notificationService) MarkRead(userID, id uint64) error {
+	notification, err := s.notificationRepo.GetNotificationByID(id)
+	if err != nil {
+		return err
+	}
+	if notification == nil || notification.UserID != userID {
+		return ErrNotificationNotFound
+	}
+	return s.notificationRepo.MarkRead(userID, id)
+}
+
+func (s *notificationService) MarkAllRead(userID uint64) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+// NotifyUser notifies a single user, loading them to check their settings. Notifications are a
+// side effect of the action that caused them, so failures are logged rather than returned.
+func (s *notificationService) NotifyUser(userID uint64, notificationType string, data map[string]interface{}) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		log.Printf("Error loading user %d to notify about %s: %v", userID, notificationType, err)
+		return
+	}
+	s.NotifyUsers([]entity.User{*user}, notificationType, data)
+}
+
+// NotifyUsers notifies the users that did not turn the notification type off, in their locale.
+// Each notification is also pushed to its user's event stream. The users' roles must be loaded.
+func (s *notificationService) NotifyUsers(users []entity.User, notificationType string, data map[string]interface{}) {
+	if key, ok := notificationSettings[notificationType]; ok {
+		var err error
+		if users, err = s.settingService.FilterUsers(key, users); err != nil {
+			log.Printf("Error fetching notification settings for %s: %v", notificationType, err)
+			return
+		}
+	}
+
+	messages := make(map[string]notificationMessage)
+	notifications := make([]entity.Notification, 0, len(users))
+	for _, user := range users {
+		locale := utils.NegotiateLocale(user.Locale)
+		message, ok := messages[locale]
+		if !ok {
+			var err error
+			if message, err = renderNotification(notificationType, locale, data); err != nil {
+				log.Printf("Error rendering %s notification: %v", notificationType, err)
+				return
+			}
+			messages[locale] = message
+		}
+		notifications = append(notifications, entity.Notification{
+			UserID: user.ID,
+			Type:   notificationType,
+			Title:  message.title,
+			Body:   message.body,
+			Data:   data,
+		})
+	}
+
+	if err := s.notificationRepo.CreateNotifications(notifications); err != nil {
+		log.Printf("Error storing %s notifications: %v", notificationType, err)
+		return
+	}
+	for _, notification := range notifications {
+		s.publisher.PublishTo(notification.UserID, enums.TopicNotification, notification)
+	}
+}
+
+func renderNotification(notificationType, locale string, data map[string]interface{}) (notificationMessage, error) {
+	message, ok := notificationMessages[locale][notificationType]
+	if !ok {
+		message, ok = notificationMessages[utils.DefaultLocale()][notificationType]
+	}
+	if !ok {
+		message, ok = notificationMessages["en"][notificationType]
+	}
+	if !ok {
+		return notificationMessage{}, errors.New("unknown notification type " + notificationType)
+	}
+
+	title, err := executeNotificationText(message.title, data)
+	if err != nil {
+		return notificationMessage{}, err
+	}
+	body, err := executeNotificationText(message.body, data)
+	if err != nil {
+		return notificationMessage{}, err
+	}
+	return notificationMessage{title: title, body: body}, nil
+}
+
+func executeNotificationText(text string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.New("notification").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
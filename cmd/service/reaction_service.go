@@ -3,75 +3,313 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 	"venecraft-back/cmd/entity"
 	"venecraft-back/cmd/repository"
 )
 
-type ReactionService interface {
-	ToggleReaction(userID, newsID uint64, reactionType string) (bool, error)
+var (
+	ErrReactionTypeNotFound = errors.New("reaction type not found")
+	ErrReactionTypeInUse    = errors.New("reaction type is in use, deactivate it instead")
+)
+
+// ReactionCount is the number of reactions of one catalog type on a news.
+type ReactionCount struct {
+	Type    string `json:"type"`
+	Emoji   string `json:"emoji"`
+	Label   string `json:"label"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"`
 }
 
-type reactionService struct {
-	reactionRepo repository.ReactionRepository
-	logRepo      repository.LogRepository
+// ReactionSummary holds the reaction counts of a news and the viewer's own reactions.
+type ReactionSummary struct {
+	Counts        []ReactionCount
+	UserReactions []string
 }
 
-func NewReactionService(reactionRepo repository.ReactionRepository, logRepo repository.LogRepository) ReactionService {
-	return &reactionService{reactionRepo, logRepo}
+// Count returns the number of reactions of the given type.
+func (rs *ReactionSummary) Count(reactionType string) int64 {
+	for _, count := range rs.Counts {
+		if count.Type == reactionType {
+			return count.Count
+		}
+	}
+	return 0
 }
 
-func (s *reactionService) ToggleReaction(userID, newsID uint64, reactionType string) (bool, error) {
-	if reactionType != "like" && reactionType != "dislike" {
-		return false, errors.New("invalid reaction type")
+// HasReacted reports whether the viewer reacted with the given type.
+func (rs *ReactionSummary) HasReacted(reactionType string) bool {
+	for _, userReaction := range rs.UserReactions {
+		if userReaction == reactionType {
+			return true
+		}
 	}
+	return false
+}
+
+// ReactionToggleResult describes the outcome of toggling a reaction.
+type ReactionToggleResult struct {
+	Type    string `json:"type"`
+	Reacted bool   `json:"reacted"`
+
+	// Removed lists the reactions of the same exclusive group that were replaced.
+	Removed   []string        `json:"removed,omitempty"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+// ReactionTypeUpdate lists the changes to apply to a reaction type. Nil fields are left untouched.
+type ReactionTypeUpdate struct {
+	Emoji          *string
+	Label          *string
+	ExclusiveGroup *string
+	SortOrder      *int
+	Active         *bool
+}
+
+type ReactionService interface {
+	ToggleReaction(userID, newsID uint64, reactionType string) (*ReactionToggleResult, error)
+	SummarizeReactions(userID uint64, newsIDs []uint64) (map[uint64]*ReactionSummary, error)
+	GetReactionTypes(includeInactive bool) ([]entity.ReactionType, error)
+	CreateReactionType(reactionType *entity.ReactionType) error
+	UpdateReactionType(key string, update ReactionTypeUpdate) (*entity.ReactionType, error)
+	DeleteReactionType(key string) error
+}
+
+type reactionService struct {
+	reactionRepo     repository.ReactionRepository
+	reactionTypeRepo repository.ReactionTypeRepository
+	newsRepo         repository.NewsRepository
+	logRepo          repository.LogRepository
+}
+
+func NewReactionService(reactionRepo repository.ReactionRepository, reactionTypeRepo repository.ReactionTypeRepository, newsRepo repository.NewsRepository, logRepo repository.LogRepository) ReactionService {
+	return &reactionService{reactionRepo, reactionTypeRepo, newsRepo, logRepo}
+}
 
-	// Check if the user already reacted with the specified type
-	reacted, err := s.reactionRepo.HasUserReacted(userID, newsID, reactionType)
+// ToggleReaction adds or removes a reaction from the catalog. Adding a reaction that belongs to an
+// exclusive group replaces the user's other reactions of that group (e.g. like and dislike).
+func (s *reactionService) ToggleReaction(userID, newsID uint64, reactionType string) (*ReactionToggleResult, error) {
+	catalog, err := s.reactionTypeRepo.GetReactionTypes(false)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	if reacted {
-		// If the user has already reacted, remove the reaction
-		err := s.reactionRepo.DeleteReaction(userID, newsID, reactionType)
-		if err != nil {
-			return false, err
+	var selected *entity.ReactionType
+	for i := range catalog {
+		if catalog[i].Key == reactionType {
+			selected = &catalog[i]
 		}
+	}
+	if selected == nil {
+		return nil, errors.New("invalid reaction type")
+	}
 
-		logEntry := entity.Log{
-			UserID:      userID,
-			Action:      fmt.Sprintf("un%s", reactionType),
-			Description: fmt.Sprintf("User with id: %d un%sd the news post with id: %d", userID, reactionType, newsID),
-			Timestamp:   time.Now(),
-		}
-		if logErr := s.logRepo.CreateLog(&logEntry); logErr != nil {
-			return false, logErr
-		}
+	news, err := s.newsRepo.GetNewsByID(newsID, false)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, ErrNewsNotFound
+	}
 
-		return false, nil
+	var exclusiveTypes []string
+	if selected.ExclusiveGroup != "" {
+		for _, other := range catalog {
+			if other.ExclusiveGroup == selected.ExclusiveGroup && other.Key != selected.Key {
+				exclusiveTypes = append(exclusiveTypes, other.Key)
+			}
+		}
 	}
 
-	// Add the new reaction
 	reaction := entity.Reaction{
 		UserID:    userID,
 		NewsID:    newsID,
 		Type:      reactionType,
 		Timestamp: time.Now(),
 	}
-	if err := s.reactionRepo.CreateReaction(&reaction); err != nil {
-		return false, err
+	reacted, changed, removed, err := s.reactionRepo.ToggleReaction(&reaction, exclusiveTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, removedType := range removed {
+		if err := s.logReaction(userID, newsID, removedType, false); err != nil {
+			return nil, err
+		}
+	}
+	if changed {
+		if err := s.logReaction(userID, newsID, reactionType, reacted); err != nil {
+			return nil, err
+		}
+	}
+
+	summaries, err := s.SummarizeReactions(userID, []uint64{newsID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReactionToggleResult{
+		Type:      reactionType,
+		Reacted:   reacted,
+		Removed:   removed,
+		Reactions: summaries[newsID].Counts,
+	}, nil
+}
+
+// reactionLogActions returns the log actions of adding and removing a reaction.
+func reactionLogActions(reactionType string) [2]string {
+	return [2]string{reactionType, "un" + reactionType}
+}
+
+func (s *reactionService) logReaction(userID, newsID uint64, reactionType string, reacted bool) error {
+	actions := reactionLogActions(reactionType)
+	action := actions[0]
+	description := fmt.Sprintf("User with id: %d reacted with %s to the news post with id: %d", userID, reactionType, newsID)
+	if !reacted {
+		action = actions[1]
+		description = fmt.Sprintf("User with id: %d removed the %s reaction from the news post with id: %d", userID, reactionType, newsID)
 	}
 
 	logEntry := entity.Log{
 		UserID:      userID,
-		Action:      reactionType,
-		Description: fmt.Sprintf("User with id: %d %sd the news post with id: %d", userID, reactionType, newsID),
+		Action:      action,
+		Description: description,
 		Timestamp:   time.Now(),
 	}
-	if logErr := s.logRepo.CreateLog(&logEntry); logErr != nil {
-		return false, logErr
+	return s.logRepo.CreateLog(&logEntry)
+}
+
+// SummarizeReactions counts the reactions of every active catalog type for the given news, along
+// with the reactions of the user.
+func (s *reactionService) SummarizeReactions(userID uint64, newsIDs []uint64) (map[uint64]*ReactionSummary, error) {
+	catalog, err := s.reactionTypeRepo.GetReactionTypes(false)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.reactionRepo.GetReactionCounts(newsIDs)
+	if err != nil {
+		return nil, err
+	}
+	countsByNews := make(map[uint64]map[string]int64)
+	for _, count := range counts {
+		if countsByNews[count.NewsID] == nil {
+			countsByNews[count.NewsID] = make(map[string]int64)
+		}
+		countsByNews[count.NewsID][count.Type] = count.Count
+	}
+
+	userReactions, err := s.reactionRepo.GetUserReactions(userID, newsIDs)
+	if err != nil {
+		return nil, err
+	}
+	reactedByNews := make(map[uint64]map[string]bool)
+	for _, reaction := range userReactions {
+		if reactedByNews[reaction.NewsID] == nil {
+			reactedByNews[reaction.NewsID] = make(map[string]bool)
+		}
+		reactedByNews[reaction.NewsID][reaction.Type] = true
+	}
+
+	summaries := make(map[uint64]*ReactionSummary, len(newsIDs))
+	for _, newsID := range newsIDs {
+		summary := &ReactionSummary{
+			Counts:        make([]ReactionCount, 0, len(catalog)),
+			UserReactions: []string{},
+		}
+		for _, reactionType := range catalog {
+			reacted := reactedByNews[newsID][reactionType.Key]
+			summary.Counts = append(summary.Counts, ReactionCount{
+				Type:    reactionType.Key,
+				Emoji:   reactionType.Emoji,
+				Label:   reactionType.Label,
+				Count:   countsByNews[newsID][reactionType.Key],
+				Reacted: reacted,
+			})
+			if reacted {
+				summary.UserReactions = append(summary.UserReactions, reactionType.Key)
+			}
+		}
+		summaries[newsID] = summary
+	}
+	return summaries, nil
+}
+
+func (s *reactionService) GetReactionTypes(includeInactive bool) ([]entity.ReactionType, error) {
+	return s.reactionTypeRepo.GetReactionTypes(includeInactive)
+}
+
+func (s *reactionService) CreateReactionType(reactionType *entity.ReactionType) error {
+	reactionType.Key = strings.ToLower(strings.TrimSpace(reactionType.Key))
+	if reactionType.Key == "" || reactionType.Emoji == "" || reactionType.Label == "" {
+		return errors.New("the key, emoji and label must be provided")
+	}
+	if len(reactionType.Key) > 32 || strings.ContainsAny(reactionType.Key, " /") {
+		return errors.New("the key must be at most 32 characters without spaces or slashes")
 	}
 
-	return true, nil
+	existing, err := s.reactionTypeRepo.GetReactionTypeByKey(reactionType.Key)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("a reaction type with this key already exists")
+	}
+
+	reactionType.ExclusiveGroup = strings.ToLower(strings.TrimSpace(reactionType.ExclusiveGroup))
+	return s.reactionTypeRepo.CreateReactionType(reactionType)
+}
+
+func (s *reactionService) UpdateReactionType(key string, update ReactionTypeUpdate) (*entity.ReactionType, error) {
+	reactionType, err := s.reactionTypeRepo.GetReactionTypeByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if reactionType == nil {
+		return nil, ErrReactionTypeNotFound
+	}
+
+	if update.Emoji != nil && *update.Emoji != "" {
+		reactionType.Emoji = *update.Emoji
+	}
+	if update.Label != nil && *update.Label != "" {
+		reactionType.Label = *update.Label
+	}
+	if update.ExclusiveGroup != nil {
+		reactionType.ExclusiveGroup = strings.ToLower(strings.TrimSpace(*update.ExclusiveGroup))
+	}
+	if update.SortOrder != nil {
+		reactionType.SortOrder = *update.SortOrder
+	}
+	if update.Active != nil {
+		reactionType.Active = *update.Active
+	}
+
+	if err := s.reactionTypeRepo.UpdateReactionType(reactionType); err != nil {
+		return nil, err
+	}
+	return reactionType, nil
+}
+
+// DeleteReactionType removes a reaction type nobody has used yet; used ones must be deactivated
+// so their history is kept.
+func (s *reactionService) DeleteReactionType(key string) error {
+	reactionType, err := s.reactionTypeRepo.GetReactionTypeByKey(key)
+	if err != nil {
+		return err
+	}
+	if reactionType == nil {
+		return ErrReactionTypeNotFound
+	}
+
+	count, err := s.reactionRepo.CountReactionsByType(key)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrReactionTypeInUse
+	}
+	return s.reactionTypeRepo.DeleteReactionType(reactionType.ID)
 }
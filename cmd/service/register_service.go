@@ -3,154 +3,217 @@ package service
 import (
 	"errors"
 	"fmt"
-	"github.com/resend/resend-go/v2"
 	"golang.org/x/crypto/bcrypt"
-	"log"
+	"strings"
+	"time"
 	"venecraft-back/cmd/email"
 	"venecraft-back/cmd/entity"
 	"venecraft-back/cmd/enums"
 	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
 )
 
 type RegisterService interface {
 	CreateRegister(register *entity.Register) error
+	ResendVerification(email string) error
+	CompleteVerification(verification *entity.EmailVerification) error
 	GetAllRegisters() ([]entity.Register, error)
-	ApproveRegister(id uint64) (*entity.User, error)
-	DenyRegister(id uint64) error
+	ApproveRegister(actor AuditActor, id uint64) (*entity.User, error)
+	DenyRegister(actor AuditActor, id uint64) error
 }
 
 type registerService struct {
-	registerRepo repository.RegisterRepository
-	userRepo     repository.UserRepository
-	roleRepo     repository.RoleRepository
-	userRoleRepo repository.UserRoleRepository
-	emailClient  *email.EmailClient
+	registerRepo     repository.RegisterRepository
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	verificationRepo repository.EmailVerificationRepository
+	settingService   SettingService
+	notifier         NotificationService
+	logService       LogService
+	templates        *email.Templates
+	senders          email.Senders
+	verification     verificationSettings
 }
 
-func NewRegisterService(registerRepo repository.RegisterRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, userRoleRepo repository.UserRoleRepository) RegisterService {
+func NewRegisterService(registerRepo repository.RegisterRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, verificationRepo repository.EmailVerificationRepository, settingService SettingService, notifier NotificationService, logService LogService, templates *email.Templates) RegisterService {
 	return &registerService{
-		registerRepo: registerRepo,
-		userRepo:     userRepo,
-		roleRepo:     roleRepo,
-		userRoleRepo: userRoleRepo,
-		emailClient:  email.GetEmailClient(),
+		registerRepo:     registerRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		verificationRepo: verificationRepo,
+		settingService:   settingService,
+		notifier:         notifier,
+		logService:       logService,
+		templates:        templates,
+		senders:          email.LoadSenders(),
+		verification:     loadVerificationSettings(),
 	}
 }
 
+// CreateRegister stores the registration request and emails a link and code to verify its address.
+// The request only reaches the admins once the address is verified.
 func (s *registerService) CreateRegister(register *entity.Register) error {
+	register.Email = strings.TrimSpace(register.Email)
+	if !utils.IsValidEmail(register.Email) {
+		return ErrInvalidEmail
+	}
+	if existingUser, _ := s.userRepo.GetUserByEmail(register.Email, false); existingUser != nil {
+		return ErrEmailTaken
+	}
+	if err := s.checkPendingRegister(register.Email); err != nil {
+		return err
+	}
+
 	hashedPassword, err := hashPassword(register.Password)
 	if err != nil {
 		return err
 	}
 	register.Password = hashedPassword
+	register.Locale = utils.NegotiateLocale(register.Locale)
+	register.AwaitingVerification = true
+	now := time.Now()
+	register.VerificationSentAt = &now
 
-	err = s.registerRepo.CreateRegister(register)
+	verification, data, err := s.verification.issue(enums.VerificationRegister, 0, register.Email)
+	if err != nil {
+		return err
+	}
+	verifyEmail, err := s.verifyEmail(register, data)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err != nil {
-			delErr := s.registerRepo.DeleteRegister(register.ID)
-			if delErr != nil {
-				log.Printf("Error cleaning up registration for user %s: %v", register.Email, delErr)
-			} else {
-				log.Printf("Cleanup: registration for user %s deleted successfully", register.Email)
-			}
-		}
-	}()
+	return s.registerRepo.CreateRegister(register, verification, *verifyEmail)
+}
 
-	admins, err := s.userRepo.GetUsersByRole(enums.RoleAdmin)
+// checkPendingRegister rejects addresses with a registration request in progress. Requests whose
+// verification expired are replaced by the new one.
+func (s *registerService) checkPendingRegister(address string) error {
+	existing, err := s.registerRepo.GetRegisterByEmail(address)
 	if err != nil {
-		return fmt.Errorf("failed to fetch admin users: %v", err)
+		return err
+	}
+	if existing == nil {
+		return nil
+	}
+	if !existing.AwaitingVerification {
+		return ErrEmailTaken
+	}
+	pending, err := s.verificationRepo.GetPendingVerificationByEmail(address, time.Now())
+	if err != nil {
+		return err
+	}
+	if pending != nil && pending.Purpose == enums.VerificationRegister {
+		return ErrVerificationPending
 	}
+	return nil
+}
 
-	adminEmails := make([]string, len(admins))
-	for i, admin := range admins {
-		adminEmails[i] = admin.Email
+// ResendVerification sends a new link and code for the registration request of the address,
+// replacing the previous ones. Nothing is sent when there is no request awaiting verification, or
+// when the latest email is too recent or too many were resent in the last hour, so the outcome
+// does not tell whether the address has a request.
+func (s *registerService) ResendVerification(address string) error {
+	register, err := s.registerRepo.GetRegisterByEmail(strings.TrimSpace(address))
+	if err != nil {
+		return err
+	}
+	if register == nil || !register.AwaitingVerification {
+		return nil
+	}
+	reserved, err := s.registerRepo.ReserveVerificationResend(register.ID, time.Now(), s.verification.resendCooldown, time.Hour, s.verification.resendLimit)
+	if err != nil || !reserved {
+		return err
 	}
 
-	err = s.sendUserConfirmationEmail(register.Email, register.Nickname)
+	verification, data, err := s.verification.issue(enums.VerificationRegister, register.ID, register.Email)
 	if err != nil {
-		log.Printf("Error sending confirmation email to user %s: %v", register.Email, err)
-		return fmt.Errorf("failed to send confirmation email to the user")
+		return err
 	}
+	verifyEmail, err := s.verifyEmail(register, data)
+	if err != nil {
+		return err
+	}
+	return s.verificationRepo.CreateVerification(verification, *verifyEmail)
+}
 
-	err = s.sendAdminNotificationEmail(adminEmails, register)
+// CompleteVerification puts the registration request in the admin queue. The confirmation email to
+// the user and the notification to the admins are queued in the outbox with it.
+func (s *registerService) CompleteVerification(verification *entity.EmailVerification) error {
+	register, err := s.registerRepo.GetRegisterByID(verification.SubjectID)
+	if err != nil || !register.AwaitingVerification {
+		return ErrVerificationInvalid
+	}
+
+	admins, err := s.userRepo.GetUsersByRole(enums.RoleAdmin)
 	if err != nil {
-		log.Printf("Error sending notification email to admins about registration by user %s: %v", register.Email, err)
-		return fmt.Errorf("failed to send registration notification email to admins")
+		return fmt.Errorf("failed to fetch admin users: %v", err)
 	}
 
-	log.Println("Registration emails sent successfully.")
-	return nil
+	confirmation, err := s.userConfirmationEmail(register)
+	if err != nil {
+		return err
+	}
+	notifications, err := s.adminNotificationEmails(admins, register)
+	if err != nil {
+		return err
+	}
+	emails := append([]entity.OutboxEmail{*confirmation}, notifications...)
+
+	return s.verificationRepo.CompleteRegisterVerification(verification, emails...)
 }
 
 func (s *registerService) GetAllRegisters() ([]entity.Register, error) {
 	return s.registerRepo.GetAllRegisters()
 }
 
-func (s *registerService) ApproveRegister(id uint64) (*entity.User, error) {
+func (s *registerService) ApproveRegister(actor AuditActor, id uint64) (*entity.User, error) {
 	register, err := s.registerRepo.GetRegisterByID(id)
-	if err != nil {
+	if err != nil || register.AwaitingVerification {
 		return nil, errors.New("registration request not found")
 	}
 
+	playerRole, err := s.roleRepo.GetRoleByName(enums.RolePlayer)
+	if err != nil {
+		return nil, errors.New("failed to assign role: PLAYER role not found")
+	}
+
 	user := &entity.User{
 		FullName: register.FullName,
 		Email:    register.Email,
 		Nickname: register.Nickname,
 		Password: register.Password,
+		Locale:   register.Locale,
 	}
 
-	err = s.userRepo.CreateUser(user)
-	if err != nil {
-		return nil, err
-	}
-
-	playerRole, err := s.roleRepo.GetRoleByName(enums.RolePlayer)
-	if err != nil {
-		return nil, errors.New("failed to assign role: PLAYER role not found")
-	}
-
-	userRole := &entity.UserRole{
-		UserID: user.ID,
-		RoleID: playerRole.ID,
-	}
-	err = s.userRoleRepo.AssignRole(userRole)
+	response, err := s.userResponseEmail(register, true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.registerRepo.DeleteRegister(id)
-	if err != nil {
+	if err := s.registerRepo.ApproveRegister(id, user, playerRole.ID, *response); err != nil {
 		return nil, err
 	}
-
-	err = s.sendUserResponseEmail(register.Email, true)
-	if err != nil {
-		log.Printf("Error sending approval email to user %s: %v", register.Email, err)
-	}
-
+	s.logService.Audit(actor, enums.AuditRegisterApprove, enums.AuditTargetRegister, register.ID, register, user)
+	s.notifier.NotifyUser(user.ID, enums.NotificationRegistrationApproved, nil)
 	return user, nil
 }
 
-func (s *registerService) DenyRegister(id uint64) error {
+func (s *registerService) DenyRegister(actor AuditActor, id uint64) error {
 	register, err := s.registerRepo.GetRegisterByID(id)
-	if err != nil {
+	if err != nil || register.AwaitingVerification {
 		return errors.New("registration request not found")
 	}
 
-	err = s.registerRepo.DeleteRegister(id)
+	response, err := s.userResponseEmail(register, false)
 	if err != nil {
 		return err
 	}
 
-	err = s.sendUserResponseEmail(register.Email, false)
-	if err != nil {
-		log.Printf("Error sending denial email to user %s: %v", register.Email, err)
+	if err := s.registerRepo.DeleteRegister(id, *response); err != nil {
+		return err
 	}
-
+	s.logService.Audit(actor, enums.AuditRegisterDeny, enums.AuditTargetRegister, register.ID, register, nil)
 	return nil
 }
 
@@ -162,82 +225,59 @@ func hashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-func (s *registerService) sendUserConfirmationEmail(userEmail string, userName string) error {
-	// Use the full relative path for the template
-	body, err := email.RenderTemplate("register/user_confirmation.html", map[string]string{"Name": userName})
-	if err != nil {
-		return fmt.Errorf("failed to render user confirmation email template: %v", err)
-	}
-
-	params := &resend.SendEmailRequest{
-		From:    "Registration Service <onboarding@jjar.lat>",
-		To:      []string{userEmail},
-		Html:    body,
-		Subject: "Registration Request Created",
-	}
-
-	sent, err := s.emailClient.SendEmail(params)
-	if err != nil {
-		return fmt.Errorf("failed to send user confirmation email: %v", err)
-	}
-
-	fmt.Println("User confirmation email sent successfully with ID:", sent.Id)
-	return nil
+func (s *registerService) verifyEmail(register *entity.Register, data map[string]interface{}) (*entity.OutboxEmail, error) {
+	data["Name"] = register.Nickname
+	return renderOutboxEmail(s.templates, "register/verify_email", register.Locale, data, email.Message{
+		From: s.senders.Onboarding,
+		To:   []string{register.Email},
+	})
 }
 
-func (s *registerService) sendAdminNotificationEmail(adminEmails []string, registerDetails *entity.Register) error {
-	// Use the full relative path for the template
-	body, err := email.RenderTemplate("register/admin_notification.html", map[string]string{
-		"FullName": registerDetails.FullName,
-		"Email":    registerDetails.Email,
-		"Nickname": registerDetails.Nickname,
+func (s *registerService) userConfirmationEmail(register *entity.Register) (*entity.OutboxEmail, error) {
+	return renderOutboxEmail(s.templates, "register/user_confirmation", register.Locale, map[string]interface{}{
+		"Name": register.Nickname,
+	}, email.Message{
+		From: s.senders.Onboarding,
+		To:   []string{register.Email},
 	})
-	if err != nil {
-		return fmt.Errorf("failed to render admin notification email template: %v", err)
-	}
-
-	params := &resend.SendEmailRequest{
-		From:    "Registration Service <onboarding@jjar.lat>",
-		To:      adminEmails,
-		Html:    body,
-		Subject: "New Registration Request for Review",
-	}
-
-	sent, err := s.emailClient.SendEmail(params)
-	if err != nil {
-		return fmt.Errorf("failed to send admin notification email: %v", err)
-	}
-
-	fmt.Println("Admin notification email sent successfully with ID:", sent.Id)
-	return nil
 }
 
-func (s *registerService) sendUserResponseEmail(userEmail string, accepted bool) error {
-	templatePath := "register/user_response.html"
-	data := map[string]string{
-		"Message": "Your registration request has been approved. Welcome to the platform!",
-	}
-	if !accepted {
-		data["Message"] = "We're sorry, but your registration request has been denied."
-	}
-
-	body, err := email.RenderTemplate(templatePath, data)
+// adminNotificationEmails builds the notification of each admin that wants them, in their locale.
+func (s *registerService) adminNotificationEmails(admins []entity.User, registerDetails *entity.Register) ([]entity.OutboxEmail, error) {
+	admins, err := s.settingService.FilterUsers(enums.SettingRegistrationStatus, admins)
 	if err != nil {
-		return fmt.Errorf("failed to render user response email template: %v", err)
-	}
-
-	params := &resend.SendEmailRequest{
-		From:    "Registration Service <onboarding@jjar.lat>",
-		To:      []string{userEmail},
-		Html:    body,
-		Subject: "Registration Request Status",
+		return nil, err
 	}
 
-	sent, err := s.emailClient.SendEmail(params)
-	if err != nil {
-		return fmt.Errorf("failed to send user response email: %v", err)
+	emails := make([]entity.OutboxEmail, 0, len(admins))
+	for _, admin := range admins {
+		unsubscribe, err := s.settingService.UnsubscribeLinks(admin.ID, enums.SettingRegistrationStatus)
+		if err != nil {
+			return nil, err
+		}
+		outboxEmail, err := renderOutboxEmail(s.templates, "register/admin_notification", utils.NegotiateLocale(admin.Locale), map[string]interface{}{
+			"FullName":       registerDetails.FullName,
+			"Email":          registerDetails.Email,
+			"Nickname":       registerDetails.Nickname,
+			"UnsubscribeURL": unsubscribe.URL,
+		}, email.Message{
+			From:    s.senders.Onboarding,
+			To:      []string{admin.Email},
+			Headers: unsubscribe.Headers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, *outboxEmail)
 	}
+	return emails, nil
+}
 
-	fmt.Println("User response email sent successfully with ID:", sent.Id)
-	return nil
+func (s *registerService) userResponseEmail(register *entity.Register, accepted bool) (*entity.OutboxEmail, error) {
+	return renderOutboxEmail(s.templates, "register/user_response", register.Locale, map[string]interface{}{
+		"Accepted": accepted,
+	}, email.Message{
+		From: s.senders.Onboarding,
+		To:   []string{register.Email},
+	})
 }
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrUnknownSetting = errors.New("unknown setting")
+	ErrUserNotFound   = errors.New("user not found")
+)
+
+// UserSettingValue is a notification setting with the value in effect for a user.
+type UserSettingValue struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// Default is the value given by the user's roles, used until the user changes the setting
+	Default bool `json:"default"`
+}
+
+// UnsubscribeLinks turn a notification setting off from an email. URL is the page linked in the
+// email body; Headers carry the one-click List-Unsubscribe link mail clients show.
+type UnsubscribeLinks struct {
+	URL     string
+	Headers map[string]string
+}
+
+// SettingService manages the notification settings of users. Every notification sender asks it
+// which users want a notification; emails users explicitly requested, such as password resets and
+// address verifications, are always sent.
+type SettingService interface {
+	GetUserSettings(userID uint64) ([]UserSettingValue, error)
+	UpdateUserSettings(userID uint64, values map[string]bool) ([]UserSettingValue, error)
+	IsEnabled(user *entity.User, key string) (bool, error)
+	FilterUsers(key string, users []entity.User) ([]entity.User, error)
+	UnsubscribeLinks(userID uint64, key string) (*UnsubscribeLinks, error)
+	Unsubscribe(token string) (string, error)
+}
+
+type settingService struct {
+	settingRepo    repository.SettingRepository
+	userRepo       repository.UserRepository
+	unsubscribeURL string
+	oneClickURL    string
+	unsubscribeTTL time.Duration
+}
+
+func NewSettingService(settingRepo repository.SettingRepository, userRepo repository.UserRepository) SettingService {
+	return &settingService{
+		settingRepo:    settingRepo,
+		userRepo:       userRepo,
+		unsubscribeURL: utils.GetEnv("UNSUBSCRIBE_URL", "http://localhost:3000/unsubscribe"),
+		oneClickURL:    utils.GetEnv("UNSUBSCRIBE_API_URL", "http://localhost:8080/api/unsubscribe"),
+		unsubscribeTTL: utils.GetEnvDuration("UNSUBSCRIBE_TOKEN_TTL", 90*24*time.Hour),
+	}
+}
+
+func (s *settingService) GetUserSettings(userID uint64) ([]UserSettingValue, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	settings, err := s.settingRepo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	userSettings, err := s.settingRepo.GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[uint64]bool, len(userSettings))
+	for _, userSetting := range userSettings {
+		overrides[userSetting.SettingID] = userSetting.IsActive
+	}
+
+	values := make([]UserSettingValue, 0, len(settings))
+	for _, setting := range settings {
+		value := UserSettingValue{
+			Key:         setting.Key,
+			Description: setting.Description,
+			Default:     enabledByDefault(&setting, user),
+		}
+		value.Enabled = value.Default
+		if enabled, ok := overrides[setting.ID]; ok {
+			value.Enabled = enabled
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// UpdateUserSettings stores the given values, by setting key. Settings left out keep their value.
+func (s *settingService) UpdateUserSettings(userID uint64, values map[string]bool) ([]UserSettingValue, error) {
+	settings, err := s.settingRepo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	settingIDs := make(map[string]uint64, len(settings))
+	for _, setting := range settings {
+		settingIDs[setting.Key] = setting.ID
+	}
+
+	userSettings := make([]entity.UserSetting, 0, len(values))
+	for key, enabled := range values {
+		settingID, ok := settingIDs[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+		}
+		userSettings = append(userSettings, entity.UserSetting{UserID: userID, SettingID: settingID, IsActive: enabled})
+	}
+
+	if _, err := s.GetUserSettings(userID); err != nil {
+		return nil, err
+	}
+	if err := s.settingRepo.SaveUserSettings(userSettings); err != nil {
+		return nil, err
+	}
+	return s.GetUserSettings(userID)
+}
+
+// IsEnabled reports whether the user wants the notifications of the setting. The user's roles must
+// be loaded.
+func (s *settingService) IsEnabled(user *entity.User, key string) (bool, error) {
+	setting, err := s.settingRepo.GetSettingByKey(key)
+	if err != nil {
+		return false, err
+	}
+	if setting == nil {
+		return true, nil
+	}
+
+	userSettings, err := s.settingRepo.GetUserSettings(user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, userSetting := range userSettings {
+		if userSetting.SettingID == setting.ID {
+			return userSetting.IsActive, nil
+		}
+	}
+	return enabledByDefault(setting, user), nil
+}
+
+// FilterUsers keeps the users that want the notifications of the setting. The users' roles must be
+// loaded.
+func (s *settingService) FilterUsers(key string, users []entity.User) ([]entity.User, error) {
+	setting, err := s.settingRepo.GetSettingByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return users, nil
+	}
+	overrides, err := s.settingRepo.GetSettingOverrides(setting.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]entity.User, 0, len(users))
+	for _, user := range users {
+		enabled, ok := overrides[user.ID]
+		if !ok {
+			enabled = enabledByDefault(setting, &user)
+		}
+		if enabled {
+			filtered = append(filtered, user)
+		}
+	}
+	return filtered, nil
+}
+
+// UnsubscribeLinks builds the links turning the setting off, which expire after the unsubscribe
+// token TTL.
+func (s *settingService) UnsubscribeLinks(userID uint64, key string) (*UnsubscribeLinks, error) {
+	token, err := utils.GenerateUnsubscribeToken(userID, key, time.Now().Add(s.unsubscribeTTL))
+	if err != nil {
+		return nil, err
+	}
+	token = url.QueryEscape(token)
+	return &UnsubscribeLinks{
+		URL: withQuery(s.unsubscribeURL, "token="+token),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + withQuery(s.oneClickURL, "token="+token) + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// Unsubscribe turns off the setting of an unsubscribe link and returns its key.
+func (s *settingService) Unsubscribe(token string) (string, error) {
+	userID, key, err := utils.ParseUnsubscribeToken(token)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.UpdateUserSettings(userID, map[string]bool{key: false}); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func enabledByDefault(setting *entity.Setting, user *entity.User) bool {
+	for _, role := range user.Roles {
+		if role != nil && slices.Contains(setting.DefaultRoles, role.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func withQuery(base, query string) string {
+	if strings.Contains(base, "?") {
+		return base + "&" + query
+	}
+	return base + "?" + query
+}
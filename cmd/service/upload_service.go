@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
+	"venecraft-back/cmd/repository"
+	"venecraft-back/cmd/storage"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadExpired        = errors.New("upload slot has expired")
+	ErrUploadIncomplete     = errors.New("the file has not been uploaded yet")
+	ErrUploadRejected       = errors.New("uploaded file was rejected")
+	ErrUploadTooLarge       = errors.New("file exceeds the maximum upload size")
+	ErrUploadTypeNotAllowed = errors.New("file type is not allowed")
+	ErrInvalidUploadSize    = errors.New("file size must be greater than zero")
+	ErrInvalidUploadHash    = errors.New("hash must be the hex encoded SHA-256 of the file")
+)
+
+// uploadExtensions maps the MIME types accepted by direct uploads to the extension of their keys.
+// The local storage derives the content type of an object from its extension.
+var uploadExtensions = map[string]string{
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+	"image/gif":       "gif",
+	"image/webp":      "webp",
+	"application/zip": "zip",
+}
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// UploadRequest describes the file a client is about to upload.
+type UploadRequest struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Hash        string
+}
+
+// UploadTicket is an upload slot with the presigned request the client must send the file with.
+type UploadTicket struct {
+	entity.UploadSlot
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+}
+
+type UploadService interface {
+	CreateUpload(ctx context.Context, uploaderID uint64, request UploadRequest) (*UploadTicket, error)
+	ConfirmUpload(ctx context.Context, uploaderID, id uint64) (*MediaItem, error)
+	DeleteExpiredUploads() (int64, error)
+}
+
+type uploadService struct {
+	uploadRepo   repository.UploadRepository
+	mediaService MediaService
+	imageService ImageService
+	storage      storage.Storage
+	maxSize      int64
+	ttl          time.Duration
+}
+
+func NewUploadService(uploadRepo repository.UploadRepository, mediaService MediaService, imageService ImageService, fileStorage storage.Storage) UploadService {
+	return &uploadService{
+		uploadRepo:   uploadRepo,
+		mediaService: mediaService,
+		imageService: imageService,
+		storage:      fileStorage,
+		maxSize:      int64(utils.GetEnvInt("DIRECT_UPLOAD_MAX_SIZE", 100<<20)),
+		ttl:          utils.GetEnvDuration("DIRECT_UPLOAD_TTL", time.Hour),
+	}
+}
+
+// CreateUpload reserves a storage key for the file and presigns the PUT request the client uploads
+// it with. The file must be uploaded and confirmed before the slot expires.
+func (s *uploadService) CreateUpload(ctx context.Context, uploaderID uint64, request UploadRequest) (*UploadTicket, error) {
+	contentType, _, err := mime.ParseMediaType(request.ContentType)
+	if err != nil {
+		return nil, ErrUploadTypeNotAllowed
+	}
+	extension, ok := uploadExtensions[contentType]
+	if !ok {
+		return nil, ErrUploadTypeNotAllowed
+	}
+	if request.Size <= 0 {
+		return nil, ErrInvalidUploadSize
+	}
+	if request.Size > s.maxSize || (isImageType(contentType) && request.Size > s.imageService.MaxSize()) {
+		return nil, ErrUploadTooLarge
+	}
+	hash := strings.ToLower(request.Hash)
+	if !sha256Pattern.MatchString(hash) {
+		return nil, ErrInvalidUploadHash
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(storage.SanitizeFileName(request.FileName), path.Ext(request.FileName))
+	if name == "" {
+		name = "file"
+	}
+	key := fmt.Sprintf("uploads/%s/%s.%s", hex.EncodeToString(token), name, extension)
+
+	slot := &entity.UploadSlot{
+		Key:         key,
+		UploadedBy:  uploaderID,
+		ContentType: contentType,
+		Size:        request.Size,
+		Hash:        hash,
+		Status:      enums.UploadStatusPending,
+		ExpiresAt:   time.Now().Add(s.ttl),
+	}
+	uploadURL, err := s.storage.PresignPut(ctx, key, contentType, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.uploadRepo.CreateUpload(slot); err != nil {
+		return nil, err
+	}
+
+	return &UploadTicket{
+		UploadSlot: *slot,
+		UploadURL:  uploadURL,
+		Method:     http.MethodPut,
+		Headers:    map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+// ConfirmUpload verifies the size, type and hash of the uploaded file against the slot and adds it
+// to the media library. Images go through the image pipeline; other files are moved out of the
+// upload area, so the presigned URL can no longer replace them. Files that fail verification are
+// deleted and the slot is rejected.
+func (s *uploadService) ConfirmUpload(ctx context.Context, uploaderID, id uint64) (*MediaItem, error) {
+	slot, err := s.uploadRepo.GetUploadByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil || slot.UploadedBy != uploaderID {
+		return nil, ErrUploadNotFound
+	}
+
+	switch slot.Status {
+	case enums.UploadStatusCompleted:
+		return s.mediaService.GetMedia(*slot.MediaID)
+	case enums.UploadStatusRejected:
+		return nil, fmt.Errorf("%w: %s", ErrUploadRejected, slot.RejectReason)
+	}
+	if time.Now().After(slot.ExpiresAt) {
+		return nil, ErrUploadExpired
+	}
+
+	info, err := s.storage.Stat(ctx, slot.Key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrUploadIncomplete
+		}
+		return nil, err
+	}
+
+	media, err := s.storeUpload(ctx, slot, info)
+	if err != nil {
+		var rejection *uploadRejection
+		if errors.As(err, &rejection) {
+			return nil, s.reject(ctx, slot, rejection.reason)
+		}
+		return nil, err
+	}
+
+	if err := s.storage.Delete(ctx, slot.Key); err != nil {
+		log.Printf("Error deleting upload %s: %v", slot.Key, err)
+	}
+	now := time.Now()
+	slot.Status = enums.UploadStatusCompleted
+	slot.MediaID = &media.ID
+	slot.CompletedAt = &now
+	if err := s.uploadRepo.UpdateUpload(slot); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// DeleteExpiredUploads forgets the slots that were never confirmed. Their files are left to the
+// media garbage collector.
+func (s *uploadService) DeleteExpiredUploads() (int64, error) {
+	return s.uploadRepo.DeleteExpiredUploads(time.Now())
+}
+
+// uploadRejection is returned when the uploaded file does not match its slot.
+type uploadRejection struct {
+	reason string
+}
+
+func (e *uploadRejection) Error() string {
+	return e.reason
+}
+
+func rejectUpload(format string, args ...interface{}) error {
+	return &uploadRejection{reason: fmt.Sprintf(format, args...)}
+}
+
+func (s *uploadService) storeUpload(ctx context.Context, slot *entity.UploadSlot, info *storage.ObjectInfo) (*MediaItem, error) {
+	if info.Size != slot.Size {
+		return nil, rejectUpload("file size is %d bytes, expected %d", info.Size, slot.Size)
+	}
+	if contentType, _, _ := mime.ParseMediaType(info.ContentType); contentType != slot.ContentType {
+		return nil, rejectUpload("file was uploaded as %s, expected %s", info.ContentType, slot.ContentType)
+	}
+
+	reader, _, err := s.storage.Get(ctx, slot.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if isImageType(slot.ContentType) {
+		return s.storeImage(ctx, slot, reader)
+	}
+	return s.storeFile(ctx, slot, reader)
+}
+
+func (s *uploadService) storeImage(ctx context.Context, slot *entity.UploadSlot, reader io.Reader) (*MediaItem, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, slot.Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyUploadHash(slot, sha256.Sum256(data)); err != nil {
+		return nil, err
+	}
+	if format, err := utils.SniffImageType(data); err != nil || "image/"+format != slot.ContentType {
+		return nil, rejectUpload("file content is not a %s image", slot.ContentType)
+	}
+
+	media, err := s.mediaService.UploadImage(ctx, slot.UploadedBy, bytes.NewReader(data))
+	if errors.Is(err, utils.ErrInvalidImage) || errors.Is(err, utils.ErrUnsupportedImage) {
+		return nil, rejectUpload("%v", err)
+	}
+	return media, err
+}
+
+// storeFile copies the file to a key derived from its hash, so identical files share their object.
+// The content is first written to a key of the slot the client cannot upload to and only moved to
+// the shared key once its hash is verified, so a slot declaring another file's hash cannot replace
+// that file.
+func (s *uploadService) storeFile(ctx context.Context, slot *entity.UploadSlot, reader io.Reader) (*MediaItem, error) {
+	header := make([]byte, 512)
+	n, err := io.ReadFull(reader, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	header = header[:n]
+	if contentType, _, _ := mime.ParseMediaType(http.DetectContentType(header)); contentType != slot.ContentType {
+		return nil, rejectUpload("file content is %s, expected %s", contentType, slot.ContentType)
+	}
+
+	verifyingKey := slot.Key + ".verifying"
+	defer func() {
+		if err := s.storage.Delete(ctx, verifyingKey); err != nil {
+			log.Printf("Error deleting upload %s: %v", verifyingKey, err)
+		}
+	}()
+
+	hash := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(header), reader), hash)
+	if err := s.storage.Put(ctx, verifyingKey, content, slot.Size, slot.ContentType); err != nil {
+		return nil, err
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	if err := verifyUploadHash(slot, sum); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("files/%s.%s", slot.Hash, uploadExtensions[slot.ContentType])
+	if _, err := s.storage.Stat(ctx, key); errors.Is(err, storage.ErrNotFound) {
+		if err := s.storage.Copy(ctx, verifyingKey, key); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return s.mediaService.CreateMedia(&entity.MediaAsset{
+		Key:         key,
+		URL:         s.storage.URL(key),
+		UploadedBy:  slot.UploadedBy,
+		ContentType: slot.ContentType,
+		Size:        slot.Size,
+		Hash:        slot.Hash,
+	})
+}
+
+func verifyUploadHash(slot *entity.UploadSlot, sum [sha256.Size]byte) error {
+	if actual := hex.EncodeToString(sum[:]); actual != slot.Hash {
+		return rejectUpload("file hash is %s, expected %s", actual, slot.Hash)
+	}
+	return nil
+}
+
+func (s *uploadService) reject(ctx context.Context, slot *entity.UploadSlot, reason string) error {
+	if err := s.storage.Delete(ctx, slot.Key); err != nil {
+		log.Printf("Error deleting rejected upload %s: %v", slot.Key, err)
+	}
+	slot.Status = enums.UploadStatusRejected
+	slot.RejectReason = reason
+	if err := s.uploadRepo.UpdateUpload(slot); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrUploadRejected, reason)
+}
@@ -4,34 +4,43 @@ import (
 	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"time"
 	"venecraft-back/cmd/email"
 	"venecraft-back/cmd/entity"
+	"venecraft-back/cmd/enums"
 	"venecraft-back/cmd/repository"
 	"venecraft-back/cmd/utils"
 )
 
 type UserService interface {
-	CreateUser(user *entity.User, roleName string) error
+	CreateUser(actor AuditActor, user *entity.User, roleName string) error
 	GetAllUsers() ([]entity.User, error)
 	GetUserByID(id uint64) (*entity.User, error)
-	UpdateUser(id uint64, user *entity.User) error
-	DeleteUser(id uint64) error
+	UpdateUser(actor AuditActor, id uint64, user *entity.User) (bool, error)
+	CompleteEmailChange(verification *entity.EmailVerification) error
+	DeleteUser(actor AuditActor, id uint64) error
 	RequestPasswordReset(email string) error
 	ResetPassword(token string, newPassword string) error
 }
 
 type userService struct {
-	userRepo    repository.UserRepository
-	roleRepo    repository.RoleRepository
-	emailClient *email.EmailClient
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	verificationRepo repository.EmailVerificationRepository
+	settingService   SettingService
+	notifier         NotificationService
+	logService       LogService
+	templates        *email.Templates
+	senders          email.Senders
+	verification     verificationSettings
 }
 
-func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository) UserService {
-	return &userService{userRepo, roleRepo, email.GetEmailClient()}
+func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, verificationRepo repository.EmailVerificationRepository, settingService SettingService, notifier NotificationService, logService LogService, templates *email.Templates) UserService {
+	return &userService{userRepo, roleRepo, verificationRepo, settingService, notifier, logService, templates, email.LoadSenders(), loadVerificationSettings()}
 }
 
-func (s *userService) CreateUser(user *entity.User, roleName string) error {
+func (s *userService) CreateUser(actor AuditActor, user *entity.User, roleName string) error {
 	if user.FullName == "" || user.Email == "" || user.Nickname == "" || user.Password == "" {
 		return errors.New("all fields are required (FullName, Email, Nickname, Password)")
 	}
@@ -71,7 +80,11 @@ func (s *userService) CreateUser(user *entity.User, roleName string) error {
 	}
 
 	// Save the user
-	return s.userRepo.CreateUser(user)
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return err
+	}
+	s.logService.Audit(actor, enums.AuditUserCreate, enums.AuditTargetUser, user.ID, nil, user)
+	return nil
 }
 
 func (s *userService) GetAllUsers() ([]entity.User, error) {
@@ -86,29 +99,38 @@ func (s *userService) GetUserByID(id uint64) (*entity.User, error) {
 	return user, nil
 }
 
-func (s *userService) UpdateUser(userID uint64, userUpdate *entity.User) error {
+// UpdateUser applies the non-empty fields of the update. A new email address is not applied until
+// it is confirmed: a link and code are sent to it instead, and the returned flag is set.
+func (s *userService) UpdateUser(actor AuditActor, userID uint64, userUpdate *entity.User) (bool, error) {
 	// Fetch the existing user from the database
 	existingUser, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if existingUser == nil {
-		return errors.New("user not found")
+		return false, errors.New("user not found")
+	}
+	previous := *existingUser
+
+	var emailChange *entity.EmailVerification
+	var emails []entity.OutboxEmail
+	if newEmail := strings.TrimSpace(userUpdate.Email); newEmail != "" && newEmail != existingUser.Email {
+		emailChange, emails, err = s.requestEmailChange(existingUser, newEmail)
+		if err != nil {
+			return false, err
+		}
 	}
 
 	if userUpdate.FullName != "" {
 		existingUser.FullName = userUpdate.FullName
 	}
-	if userUpdate.Email != "" {
-		existingUser.Email = userUpdate.Email
-	}
 	if userUpdate.Nickname != "" {
 		existingUser.Nickname = userUpdate.Nickname
 	}
 	if userUpdate.Password != "" {
 		hashedPassword, err := hashPassword(userUpdate.Password)
 		if err != nil {
-			return errors.New("failed to hash password")
+			return false, errors.New("failed to hash password")
 		}
 		existingUser.Password = hashedPassword
 	}
@@ -118,22 +140,131 @@ func (s *userService) UpdateUser(userID uint64, userUpdate *entity.User) error {
 	if !userUpdate.RecoverPasswordTokenExpires.IsZero() {
 		existingUser.RecoverPasswordTokenExpires = userUpdate.RecoverPasswordTokenExpires
 	}
+	if userUpdate.AvatarURL != "" {
+		existingUser.AvatarURL = userUpdate.AvatarURL
+	}
+	if userUpdate.Locale != "" {
+		if !utils.IsSupportedLocale(userUpdate.Locale) {
+			return false, errors.New("unsupported locale")
+		}
+		existingUser.Locale = utils.NormalizeLocale(userUpdate.Locale)
+	}
 	if userUpdate.Roles != nil {
 		existingUser.Roles = userUpdate.Roles
 	}
 	existingUser.IsActive = userUpdate.IsActive
 
-	return s.userRepo.UpdateUser(existingUser)
+	if err := s.userRepo.UpdateUser(existingUser); err != nil {
+		return false, err
+	}
+	s.logService.Audit(actor, enums.AuditUserUpdate, enums.AuditTargetUser, existingUser.ID, &previous, existingUser)
+	if !sameRoles(previous.Roles, existingUser.Roles) {
+		s.logService.Audit(actor, enums.AuditUserRoleChange, enums.AuditTargetUser, existingUser.ID,
+			map[string]interface{}{"roles": previous.Roles}, map[string]interface{}{"roles": existingUser.Roles})
+	}
+	if userUpdate.Password != "" {
+		s.notifier.NotifyUser(existingUser.ID, enums.NotificationPasswordChanged, nil)
+	}
+	if emailChange == nil {
+		return false, nil
+	}
+	return true, s.verificationRepo.CreateVerification(emailChange, emails...)
+}
+
+// requestEmailChange prepares the verification of the user's new address and the email asking to
+// confirm it.
+func (s *userService) requestEmailChange(user *entity.User, newEmail string) (*entity.EmailVerification, []entity.OutboxEmail, error) {
+	if !utils.IsValidEmail(newEmail) {
+		return nil, nil, ErrInvalidEmail
+	}
+	if existingUser, _ := s.userRepo.GetUserByEmail(newEmail, false); existingUser != nil {
+		return nil, nil, ErrEmailTaken
+	}
+
+	verification, data, err := s.verification.issue(enums.VerificationEmailChange, user.ID, newEmail)
+	if err != nil {
+		return nil, nil, err
+	}
+	data["Name"] = user.Nickname
+	confirmEmail, err := renderOutboxEmail(s.templates, "account/confirm_email_change", user.Locale, data, email.Message{
+		From: s.senders.Support,
+		To:   []string{newEmail},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return verification, []entity.OutboxEmail{*confirmEmail}, nil
 }
 
-func (s *userService) DeleteUser(id uint64) error {
-	user, err := s.userRepo.GetUserByID(id)
+// CompleteEmailChange replaces the user's address with the confirmed one and lets the old address
+// know about it, unless the user turned security alerts off.
+func (s *userService) CompleteEmailChange(verification *entity.EmailVerification) error {
+	user, err := s.userRepo.GetUserByID(verification.SubjectID)
+	if err != nil || user == nil {
+		return ErrVerificationInvalid
+	}
+	if existingUser, _ := s.userRepo.GetUserByEmail(verification.Email, false); existingUser != nil {
+		return ErrEmailTaken
+	}
+
+	alert, err := s.settingService.IsEnabled(user, enums.SettingSecurityAlerts)
 	if err != nil {
+		return err
+	}
+	if !alert {
+		return s.verificationRepo.CompleteEmailChange(verification)
+	}
+
+	unsubscribe, err := s.settingService.UnsubscribeLinks(user.ID, enums.SettingSecurityAlerts)
+	if err != nil {
+		return err
+	}
+	notice, err := renderOutboxEmail(s.templates, "account/email_changed", user.Locale, map[string]interface{}{
+		"Name":           user.Nickname,
+		"NewEmail":       verification.Email,
+		"ChangedAt":      time.Now(),
+		"UnsubscribeURL": unsubscribe.URL,
+	}, email.Message{
+		From:    s.senders.Support,
+		To:      []string{user.Email},
+		Headers: unsubscribe.Headers,
+	})
+	if err != nil {
+		return err
+	}
+	return s.verificationRepo.CompleteEmailChange(verification, *notice)
+}
+
+func (s *userService) DeleteUser(actor AuditActor, id uint64) error {
+	user, err := s.userRepo.GetUserByID(id)
+	if err != nil || user == nil {
 		return errors.New("user not found")
 	}
+	previous := *user
 
 	user.IsActive = false
-	return s.userRepo.UpdateUser(user)
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+	s.logService.Audit(actor, enums.AuditUserDelete, enums.AuditTargetUser, user.ID, &previous, user)
+	return nil
+}
+
+// sameRoles reports whether both lists hold the same roles, in any order.
+func sameRoles(a, b []*entity.Role) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[uint64]bool, len(a))
+	for _, role := range a {
+		ids[role.ID] = true
+	}
+	for _, role := range b {
+		if !ids[role.ID] {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *userService) RequestPasswordReset(email string) error {
@@ -149,11 +280,15 @@ func (s *userService) RequestPasswordReset(email string) error {
 
 	user.RecoverPasswordToken = token
 	user.RecoverPasswordTokenExpires = expiration
-	if err := s.userRepo.UpdateUser(user); err != nil {
+
+	resetEmail, err := s.passwordResetEmail(user, token)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdateUser(user, *resetEmail); err != nil {
 		return fmt.Errorf("failed to save reset token: %v", err)
 	}
-
-	return s.emailClient.SendPasswordResetEmail(user.Nickname, user.Email, token)
+	return nil
 }
 
 func (s *userService) ResetPassword(token, newPassword string) error {
@@ -171,5 +306,20 @@ func (s *userService) ResetPassword(token, newPassword string) error {
 	user.RecoverPasswordToken = ""
 	user.RecoverPasswordTokenExpires = time.Time{}
 
-	return s.userRepo.UpdateUser(user)
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+	s.notifier.NotifyUser(user.ID, enums.NotificationPasswordChanged, nil)
+	return nil
+}
+
+func (s *userService) passwordResetEmail(user *entity.User, token string) (*entity.OutboxEmail, error) {
+	return renderOutboxEmail(s.templates, "password_reset/password_reset", user.Locale, map[string]interface{}{
+		"Username":    user.Nickname,
+		"Token":       token,
+		"RequestDate": time.Now(),
+	}, email.Message{
+		From: s.senders.Support,
+		To:   []string{user.Email},
+	})
 }
@@ -0,0 +1,357 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultLocalMaxUpload is the largest body accepted on presigned local uploads.
+const defaultLocalMaxUpload = 100 << 20
+
+// LocalConfig configures the local disk storage, meant for development and tests.
+type LocalConfig struct {
+	// Root is the directory the objects are written to.
+	Root string
+
+	// BaseURL is the URL the handler is mounted at, e.g. "http://localhost:8080/files".
+	BaseURL string
+
+	// Secret signs presigned URLs. A random secret is generated when empty, which invalidates
+	// pending presigned URLs on restart.
+	Secret string
+
+	// MaxUploadSize limits presigned uploads, in bytes.
+	MaxUploadSize int64
+
+	// PublicPrefixes are the key prefixes served without a signature. Every other object can only be
+	// downloaded through a presigned URL.
+	PublicPrefixes []string
+}
+
+// LocalStorage stores objects on the local disk and serves them over HTTP through Handler.
+type LocalStorage struct {
+	root           string
+	baseURL        string
+	secret         []byte
+	maxUploadSize  int64
+	publicPrefixes []string
+}
+
+func NewLocalStorage(cfg LocalConfig) (*LocalStorage, error) {
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create storage directory %s: %w", root, err)
+	}
+
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	maxUploadSize := cfg.MaxUploadSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultLocalMaxUpload
+	}
+
+	return &LocalStorage{
+		root:           root,
+		baseURL:        strings.TrimSuffix(cfg.BaseURL, "/"),
+		secret:         secret,
+		maxUploadSize:  maxUploadSize,
+		publicPrefixes: cfg.PublicPrefixes,
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	filePath, _ := s.path(key)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, localError(err)
+	}
+	return file, info, nil
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return nil, localError(err)
+	}
+	if stat.IsDir() {
+		return nil, ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = sniffContentType(filePath)
+	}
+
+	cleanKey, _ := CleanKey(key)
+	return &ObjectInfo{
+		Key:          cleanKey,
+		Size:         stat.Size(),
+		ContentType:  contentType,
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(s.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		relative, err := filepath.Rel(s.root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  mime.TypeByExtension(path.Ext(key)),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	return objects, err
+}
+
+func (s *LocalStorage) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	source, info, err := s.Get(ctx, sourceKey)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	return s.Put(ctx, destinationKey, source, info.Size, info.ContentType)
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) PresignPut(ctx context.Context, key, contentType string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, contentType, expires)
+}
+
+func (s *LocalStorage) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, "", expires)
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// MountPath is the path of BaseURL, where Handler is expected to be mounted.
+func (s *LocalStorage) MountPath() string {
+	parsed, err := url.Parse(s.baseURL)
+	if err != nil || parsed.Path == "" {
+		return "/files"
+	}
+	return parsed.Path
+}
+
+// Handler serves the stored objects with GET and HEAD, public ones freely and the others through
+// presigned URLs, and accepts presigned uploads with PUT.
+// It expects the object key as the request path, so it must be mounted with http.StripPrefix.
+func (s *LocalStorage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			s.serveObject(w, r, key)
+		case http.MethodPut:
+			s.receiveObject(w, r, key)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func (s *LocalStorage) serveObject(w http.ResponseWriter, r *http.Request, key string) {
+	if !s.isPublic(key) && !s.verify(http.MethodGet, key, "", r.URL.Query()) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	file, info, err := s.Get(r.Context(), key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, path.Base(info.Key), info.LastModified, file.(io.ReadSeeker))
+}
+
+func (s *LocalStorage) receiveObject(w http.ResponseWriter, r *http.Request, key string) {
+	contentType := r.Header.Get("Content-Type")
+	if !s.verify(http.MethodPut, key, contentType, r.URL.Query()) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+	if err := s.Put(r.Context(), key, body, r.ContentLength, contentType); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *LocalStorage) presign(method, key, contentType string, expires time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", s.sign(method, key, contentType, expiresAt))
+	return s.URL(key) + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorage) verify(method, key, contentType string, query url.Values) bool {
+	key, err := CleanKey(key)
+	if err != nil {
+		return false
+	}
+	expiresAt := query.Get("expires")
+	expiresUnix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		return false
+	}
+	expected := s.sign(method, key, contentType, expiresAt)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+func (s *LocalStorage) sign(method, key, contentType, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{method, key, contentType, expiresAt}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// isPublic reports whether the object can be downloaded without a signature.
+func (s *LocalStorage) isPublic(key string) bool {
+	key, err := CleanKey(key)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range s.publicPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// path maps a key to a file under the root directory.
+func (s *LocalStorage) path(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func sniffContentType(filePath string) string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer file.Close()
+
+	buffer := make([]byte, 512)
+	n, _ := io.ReadFull(file, buffer)
+	return http.DetectContentType(buffer[:n])
+}
+
+func localError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"venecraft-back/cmd/utils"
+)
+
+var (
+	ErrNotFound   = errors.New("storage object not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Storage stores uploaded files under slash separated keys such as "uploads/news/banner.png".
+type Storage interface {
+	// Put stores the content under the key, replacing any existing object.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get opens the object for reading. The caller must close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+
+	// Stat returns the metadata of the object without reading it.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// List returns every object whose key starts with the prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// Copy stores a copy of the source object under the destination key, replacing any existing
+	// object.
+	Copy(ctx context.Context, sourceKey, destinationKey string) error
+
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// PresignPut returns a URL the client can upload the object to with an HTTP PUT until it expires.
+	PresignPut(ctx context.Context, key, contentType string, expires time.Duration) (string, error)
+
+	// PresignGet returns a URL the object can be downloaded from until it expires.
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+
+	// URL returns the public URL of the object.
+	URL(key string) string
+}
+
+// PublicPrefixes are the key prefixes of the media anyone may download: processed images and
+// verified uploads. The local storage only serves other objects through presigned URLs.
+var PublicPrefixes = []string{"images/", "files/"}
+
+// New builds the storage of the uploaded media with the backend selected by STORAGE_DRIVER ("s3" or
+// "local"), which must be set explicitly.
+func New(ctx context.Context) (Storage, error) {
+	driver, err := storageDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	switch driver {
+	case "s3":
+		return NewS3Storage(ctx, S3Config{
+			Bucket:         utils.GetEnv("S3_BUCKET_NAME", ""),
+			Region:         utils.GetEnv("AWS_REGION", ""),
+			Endpoint:       utils.GetEnv("S3_ENDPOINT", ""),
+			PublicURL:      utils.GetEnv("S3_PUBLIC_URL", ""),
+			ForcePathStyle: utils.GetEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+		})
+	default:
+		return newLocalStorage(LocalConfig{
+			Root:           utils.GetEnv("LOCAL_STORAGE_DIR", "./uploads"),
+			BaseURL:        utils.GetEnv("LOCAL_STORAGE_URL", "http://localhost:8080/files"),
+			Secret:         utils.GetEnv("LOCAL_STORAGE_SECRET", ""),
+			MaxUploadSize:  int64(utils.GetEnvInt("LOCAL_STORAGE_MAX_UPLOAD", defaultLocalMaxUpload)),
+			PublicPrefixes: PublicPrefixes,
+		})
+	}
+}
+
+// NewAudit builds the private storage of the audit log checkpoints and archives, with the same
+// backend as New. On S3 it is its own bucket, AUDIT_S3_BUCKET_NAME, which must not be public; on the
+// local disk it is its own directory whose objects are only served through presigned URLs.
+func NewAudit(ctx context.Context) (Storage, error) {
+	driver, err := storageDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	switch driver {
+	case "s3":
+		bucket := utils.GetEnv("AUDIT_S3_BUCKET_NAME", "")
+		if bucket == "" || bucket == utils.GetEnv("S3_BUCKET_NAME", "") {
+			return nil, errors.New("AUDIT_S3_BUCKET_NAME must be set to a private bucket other than S3_BUCKET_NAME")
+		}
+		return NewS3Storage(ctx, S3Config{
+			Bucket:         bucket,
+			Region:         utils.GetEnv("AWS_REGION", ""),
+			Endpoint:       utils.GetEnv("S3_ENDPOINT", ""),
+			ForcePathStyle: utils.GetEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+		})
+	default:
+		return newLocalStorage(LocalConfig{
+			Root:    utils.GetEnv("AUDIT_STORAGE_DIR", "./audit"),
+			BaseURL: utils.GetEnv("AUDIT_STORAGE_URL", "http://localhost:8080/audit-files"),
+			Secret:  utils.GetEnv("LOCAL_STORAGE_SECRET", ""),
+		})
+	}
+}
+
+// newLocalStorage returns the local storage as a Storage, nil when it fails.
+func newLocalStorage(cfg LocalConfig) (Storage, error) {
+	local, err := NewLocalStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return local, nil
+}
+
+func storageDriver() (string, error) {
+	switch driver := utils.GetEnv("STORAGE_DRIVER", ""); driver {
+	case "s3", "local":
+		return driver, nil
+	case "":
+		return "", errors.New("STORAGE_DRIVER must be set to s3 or local")
+	default:
+		return "", fmt.Errorf("unknown storage driver: %s", driver)
+	}
+}
+
+// CleanKey validates a key and normalizes it, rejecting absolute paths and parent references.
+func CleanKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
+
+// KeyFromURL returns the key of an object from its public URL, if the URL belongs to the storage.
+func KeyFromURL(store Storage, url string) (string, bool) {
+	base := strings.TrimSuffix(store.URL(""), "/") + "/"
+	if !strings.HasPrefix(url, base) {
+		return "", false
+	}
+	key, err := CleanKey(strings.TrimPrefix(url, base))
+	return key, err == nil
+}
+
+// SanitizeFileName keeps letters, digits, dots, dashes and underscores of a client provided file
+// name so it can be used as part of a key.
+func SanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	sanitized = strings.TrimLeft(sanitized, ".")
+	if sanitized == "" {
+		return "file"
+	}
+	return sanitized
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetEnv returns the value of the environment variable or the fallback when it is unset.
+func GetEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvDuration parses the environment variable as a time.Duration (e.g. "30s", "5m").
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s (%q), using %s", key, value, fallback)
+		return fallback
+	}
+	return duration
+}
+
+// GetEnvInt parses the environment variable as an integer.
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s (%q), using %d", key, value, fallback)
+		return fallback
+	}
+	return number
+}
+
+// RequireEnv returns an error naming the environment variables of the list that are not set, so
+// the server refuses to start without its secrets.
+func RequireEnv(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if GetEnv(key, "") == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// MaxImagePixels bounds the decoded size of an image to protect against decompression bombs.
+	MaxImagePixels = 40_000_000
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type, expected JPEG, PNG, GIF or WebP")
+	ErrInvalidImage     = errors.New("invalid image")
+)
+
+// ImageVariant is a resized copy of an uploaded image.
+type ImageVariant struct {
+	Name     string
+	MaxWidth int
+}
+
+// ImageVariants lists the resized copies generated for every uploaded image.
+var ImageVariants = []ImageVariant{
+	{Name: "medium", MaxWidth: 960},
+	{Name: "thumbnail", MaxWidth: 320},
+}
+
+// EncodedImage is an image re-encoded without any of the metadata of the upload.
+type EncodedImage struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// ProcessedImage is an uploaded image after validation, re-encoding and resizing.
+type ProcessedImage struct {
+	// Hash is the SHA-256 of the re-encoded original, used to build the storage keys.
+	Hash     string
+	Original EncodedImage
+	Variants map[string]EncodedImage
+}
+
+// SniffImageType identifies the image format from its magic bytes.
+func SniffImageType(header []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg", nil
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return "png", nil
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return "gif", nil
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP")):
+		return "webp", nil
+	default:
+		return "", ErrUnsupportedImage
+	}
+}
+
+// ProcessImage validates an uploaded image by its magic bytes and dimensions, re-encodes it to
+// strip EXIF and other metadata and generates the resized variants. Photos are stored as JPEG;
+// PNG, GIF and WebP are stored as PNG to keep transparency (animated GIFs keep their first frame).
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	format, err := SniffImageType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := decodeImageConfig(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return nil, fmt.Errorf("%w: dimensions %dx%d are not allowed", ErrInvalidImage, config.Width, config.Height)
+	}
+
+	img, err := decodeImage(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	encodeAsJPEG := format == "jpeg"
+	original, err := encodeImage(img, encodeAsJPEG)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(original.Data)
+	processed := &ProcessedImage{
+		Hash:     hex.EncodeToString(hash[:]),
+		Original: *original,
+		Variants: make(map[string]EncodedImage, len(ImageVariants)),
+	}
+	for _, variant := range ImageVariants {
+		encoded, err := encodeImage(resizeToWidth(img, variant.MaxWidth), encodeAsJPEG)
+		if err != nil {
+			return nil, err
+		}
+		processed.Variants[variant.Name] = *encoded
+	}
+	return processed, nil
+}
+
+func decodeImageConfig(format string, data []byte) (image.Config, error) {
+	reader := bytes.NewReader(data)
+	switch format {
+	case "jpeg":
+		return jpeg.DecodeConfig(reader)
+	case "png":
+		return png.DecodeConfig(reader)
+	case "gif":
+		return gif.DecodeConfig(reader)
+	default:
+		return webp.DecodeConfig(reader)
+	}
+}
+
+func decodeImage(format string, data []byte) (image.Image, error) {
+	reader := bytes.NewReader(data)
+	switch format {
+	case "jpeg":
+		return jpeg.Decode(reader)
+	case "png":
+		return png.Decode(reader)
+	case "gif":
+		return gif.Decode(reader)
+	default:
+		return webp.Decode(reader)
+	}
+}
+
+func encodeImage(img image.Image, asJPEG bool) (*EncodedImage, error) {
+	var buffer bytes.Buffer
+	encoded := &EncodedImage{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+
+	if asJPEG {
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %v", err)
+		}
+		encoded.ContentType, encoded.Extension = "image/jpeg", "jpg"
+	} else {
+		if err := png.Encode(&buffer, img); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %v", err)
+		}
+		encoded.ContentType, encoded.Extension = "image/png", "png"
+	}
+
+	encoded.Data = buffer.Bytes()
+	return encoded, nil
+}
+
+// resizeToWidth scales the image down to the given width, keeping its aspect ratio. Images that
+// are already narrower are returned as is.
+func resizeToWidth(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return img
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
+	return resized
+}
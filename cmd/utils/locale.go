@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// SupportedLocales returns the locales content can be served in, configured through
+// SUPPORTED_LOCALES as a comma separated list. The default locale is always included.
+func SupportedLocales() []string {
+	locales := []string{DefaultLocale()}
+	for _, locale := range strings.Split(GetEnv("SUPPORTED_LOCALES", "es,en"), ",") {
+		locale = NormalizeLocale(locale)
+		if locale != "" && !contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// DefaultLocale is the locale used when no preference matches, configured through DEFAULT_LOCALE.
+func DefaultLocale() string {
+	return NormalizeLocale(GetEnv("DEFAULT_LOCALE", "es"))
+}
+
+// IsSupportedLocale reports whether the locale is one of the supported locales.
+func IsSupportedLocale(locale string) bool {
+	return contains(SupportedLocales(), NormalizeLocale(locale))
+}
+
+// NormalizeLocale reduces a language tag such as "en-US" to its base language ("en").
+func NormalizeLocale(locale string) string {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil {
+		return ""
+	}
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// NegotiateLocale picks the supported locale that best matches the given preferences, tried in
+// order. Each preference may be a single tag or a full Accept-Language header.
+func NegotiateLocale(preferences ...string) string {
+	supported := SupportedLocales()
+	tags := make([]language.Tag, 0, len(supported))
+	for _, locale := range supported {
+		tags = append(tags, language.Make(locale))
+	}
+	matcher := language.NewMatcher(tags)
+
+	for _, preference := range preferences {
+		if strings.TrimSpace(preference) == "" {
+			continue
+		}
+		desired, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil || len(desired) == 0 {
+			continue
+		}
+		if _, index, confidence := matcher.Match(desired...); confidence != language.No {
+			return supported[index]
+		}
+	}
+	return supported[0]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
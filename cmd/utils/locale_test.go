@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	assert.Equal(t, "en", NormalizeLocale("en-US"))
+	assert.Equal(t, "es", NormalizeLocale(" es-419 "))
+	assert.Equal(t, "pt", NormalizeLocale("pt_BR"))
+	assert.Equal(t, "", NormalizeLocale("not a locale"))
+}
+
+func TestSupportedLocales(t *testing.T) {
+	t.Setenv("DEFAULT_LOCALE", "en-GB")
+	t.Setenv("SUPPORTED_LOCALES", "es, fr-FR,en,bogus locale")
+
+	assert.Equal(t, []string{"en", "es", "fr"}, SupportedLocales())
+	assert.True(t, IsSupportedLocale("fr-CA"))
+	assert.False(t, IsSupportedLocale("de"))
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	t.Setenv("DEFAULT_LOCALE", "es")
+	t.Setenv("SUPPORTED_LOCALES", "es,en")
+
+	tests := []struct {
+		name        string
+		preferences []string
+		want        string
+	}{
+		{"no preference", nil, "es"},
+		{"blank preferences", []string{"", "  "}, "es"},
+		{"exact match", []string{"en"}, "en"},
+		{"regional variant", []string{"en-US"}, "en"},
+		{"accept language header", []string{"de-DE,de;q=0.9,en;q=0.8"}, "en"},
+		{"first preference wins", []string{"en", "es"}, "en"},
+		{"unsupported falls through", []string{"de", "en-GB"}, "en"},
+		{"nothing supported", []string{"de,fr;q=0.5"}, "es"},
+		{"malformed header", []string{";;;"}, "es"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateLocale(tt.preferences...))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrLogCheckpointSecretMissing = errors.New("LOG_CHECKPOINT_SECRET is not set")
+
+func logCheckpointSecret() ([]byte, error) {
+	secret := GetEnv("LOG_CHECKPOINT_SECRET", "")
+	if secret == "" {
+		return nil, ErrLogCheckpointSecretMissing
+	}
+	return []byte(secret), nil
+}
+
+// SignLogCheckpoint signs the payload of an audit log checkpoint or archive record.
+func SignLogCheckpoint(payload []byte) (string, error) {
+	secret, err := logCheckpointSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyLogCheckpoint reports whether the signature was made by SignLogCheckpoint for the payload.
+func VerifyLogCheckpoint(payload []byte, signature string) (bool, error) {
+	expected, err := SignLogCheckpoint(payload)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal([]byte(signature), []byte(expected)), nil
+}
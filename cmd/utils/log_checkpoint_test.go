@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogCheckpointSignature(t *testing.T) {
+	t.Setenv("LOG_CHECKPOINT_SECRET", "test-secret")
+	payload := []byte(`{"last_log_id":7,"last_hash":"abc"}`)
+
+	signature, err := SignLogCheckpoint(payload)
+	require.NoError(t, err)
+
+	valid, err := VerifyLogCheckpoint(payload, signature)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = VerifyLogCheckpoint([]byte(`{"last_log_id":8,"last_hash":"abc"}`), signature)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	t.Setenv("LOG_CHECKPOINT_SECRET", "another-secret")
+	valid, err = VerifyLogCheckpoint(payload, signature)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestLogCheckpointRequiresSecret(t *testing.T) {
+	t.Setenv("LOG_CHECKPOINT_SECRET", "")
+
+	_, err := SignLogCheckpoint([]byte("payload"))
+	assert.ErrorIs(t, err, ErrLogCheckpointSecretMissing)
+	_, err = VerifyLogCheckpoint([]byte("payload"), "signature")
+	assert.ErrorIs(t, err, ErrLogCheckpointSecretMissing)
+}
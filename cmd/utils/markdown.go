@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// ExcerptLength is the maximum number of characters kept in generated excerpts.
+const ExcerptLength = 280
+
+var (
+	// Cell alignment is rendered as the align attribute: the policy strips style attributes.
+	markdown = goldmark.New(goldmark.WithExtensions(extension.NewTable(
+		extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute),
+	)))
+
+	// htmlPolicy is the allowlist applied to rendered Markdown before it reaches clients.
+	htmlPolicy = newHTMLPolicy()
+
+	// textPolicy strips every tag, used to build plain text excerpts.
+	textPolicy = bluemonday.StrictPolicy()
+)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("align").OnElements("th", "td")
+	policy.RequireNoReferrerOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+// RenderMarkdown converts CommonMark (with tables) to HTML that is safe to embed in the launcher.
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
+
+// GenerateExcerpt builds a plain text summary from rendered HTML, cut on a word boundary.
+func GenerateExcerpt(renderedHTML string, maxLength int) string {
+	text := html.UnescapeString(textPolicy.Sanitize(renderedHTML))
+	text = strings.Join(strings.Fields(text), " ")
+
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:maxLength])
+	if idx := strings.LastIndex(cut, " "); idx > 0 {
+		cut = cut[:idx]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
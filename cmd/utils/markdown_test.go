@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	rendered, err := RenderMarkdown("# Title\n\nSome **bold** text.\n\n| a | b |\n|---|:-:|\n| 1 | 2 |\n")
+	require.NoError(t, err)
+
+	assert.Contains(t, rendered, "<h1>Title</h1>")
+	assert.Contains(t, rendered, "<strong>bold</strong>")
+	assert.Contains(t, rendered, "<table>")
+	assert.Contains(t, rendered, `<th align="center">b</th>`)
+}
+
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		forbidden []string
+	}{
+		{"script tag", "hello <script>alert(1)</script>", []string{"<script"}},
+		{"event handler", `<img src="x.png" onerror="alert(1)">`, []string{"onerror"}},
+		{"javascript link", "[click](javascript:alert(1))", []string{"javascript:"}},
+		{"iframe", `<iframe src="https://example.com"></iframe>`, []string{"<iframe"}},
+		{"style attribute", `<p style="position:fixed">x</p>`, []string{"style="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := RenderMarkdown(tt.source)
+			require.NoError(t, err)
+			for _, forbidden := range tt.forbidden {
+				assert.NotContains(t, rendered, forbidden)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownExternalLinks(t *testing.T) {
+	rendered, err := RenderMarkdown("[site](https://example.com)")
+	require.NoError(t, err)
+
+	assert.Contains(t, rendered, `href="https://example.com"`)
+	assert.Contains(t, rendered, `target="_blank"`)
+	assert.Contains(t, rendered, "noreferrer")
+}
+
+func TestGenerateExcerpt(t *testing.T) {
+	assert.Equal(t, "Title Some bold text.", GenerateExcerpt("<h1>Title</h1>\n<p>Some <strong>bold</strong>   text.</p>", 100))
+	assert.Equal(t, "Fish & chips", GenerateExcerpt("<p>Fish &amp; chips</p>", 100))
+
+	excerpt := GenerateExcerpt("<p>one two three, four five</p>", 16)
+	assert.Equal(t, "one two three…", excerpt)
+
+	long := GenerateExcerpt("<p>"+strings.Repeat("ñandú ", 100)+"</p>", ExcerptLength)
+	assert.LessOrEqual(t, len([]rune(long)), ExcerptLength+1)
+	assert.True(t, strings.HasSuffix(long, "ñandú…"))
+}
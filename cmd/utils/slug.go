@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a display name like "Actualizaciones del Servidor" into "actualizaciones-del-servidor".
+func Slugify(value string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	normalized, _, err := transform.String(stripAccents, value)
+	if err != nil {
+		normalized = value
+	}
+	slug := nonSlugChars.ReplaceAllString(strings.ToLower(normalized), "-")
+	return strings.Trim(slug, "-")
+}
+
+// NormalizeTags trims, lowercases and de-duplicates tag names, dropping empty ones.
+func NormalizeTags(tags []string, maxLength int) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len([]rune(tag)) > maxLength {
+			tag = string([]rune(tag)[:maxLength])
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidUnsubscribeToken  = errors.New("invalid unsubscribe token")
+	ErrExpiredUnsubscribeToken  = errors.New("unsubscribe link has expired")
+	ErrUnsubscribeSecretMissing = errors.New("UNSUBSCRIBE_SECRET is not set")
+)
+
+func unsubscribeSecret() ([]byte, error) {
+	secret := GetEnv("UNSUBSCRIBE_SECRET", "")
+	if secret == "" {
+		return nil, ErrUnsubscribeSecretMissing
+	}
+	return []byte(secret), nil
+}
+
+// GenerateUnsubscribeToken signs the user and setting an unsubscribe link turns off, valid until
+// the given moment.
+func GenerateUnsubscribeToken(userID uint64, settingKey string, expiresAt time.Time) (string, error) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(
+		strconv.FormatUint(userID, 10) + ":" + settingKey + ":" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	signature, err := signUnsubscribePayload(payload)
+	if err != nil {
+		return "", err
+	}
+	return payload + "." + signature, nil
+}
+
+// ParseUnsubscribeToken returns the user and setting of a token made by GenerateUnsubscribeToken
+// that has not expired yet.
+func ParseUnsubscribeToken(token string) (uint64, string, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+	expected, err := signUnsubscribePayload(payload)
+	if err != nil {
+		return 0, "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+	fields := strings.Split(string(decoded), ":")
+	if len(fields) != 3 {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+	userID, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+	expiresAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidUnsubscribeToken
+	}
+	if time.Now().Unix() > expiresAt {
+		return 0, "", ErrExpiredUnsubscribeToken
+	}
+	return userID, fields[1], nil
+}
+
+func signUnsubscribePayload(payload string) (string, error) {
+	secret, err := unsubscribeSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsubscribeTokenRoundTrip(t *testing.T) {
+	t.Setenv("UNSUBSCRIBE_SECRET", "test-secret")
+
+	token, err := GenerateUnsubscribeToken(42, "news_published", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	userID, settingKey, err := ParseUnsubscribeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), userID)
+	assert.Equal(t, "news_published", settingKey)
+}
+
+func TestUnsubscribeTokenExpired(t *testing.T) {
+	t.Setenv("UNSUBSCRIBE_SECRET", "test-secret")
+
+	token, err := GenerateUnsubscribeToken(42, "news_published", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, _, err = ParseUnsubscribeToken(token)
+	assert.ErrorIs(t, err, ErrExpiredUnsubscribeToken)
+}
+
+func TestUnsubscribeTokenTampered(t *testing.T) {
+	t.Setenv("UNSUBSCRIBE_SECRET", "test-secret")
+
+	token, err := GenerateUnsubscribeToken(42, "news_published", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	other, err := GenerateUnsubscribeToken(43, "news_published", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	payload, _, _ := strings.Cut(other, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	for _, forged := range []string{payload + "." + signature, token + "x", "garbage", ""} {
+		_, _, err := ParseUnsubscribeToken(forged)
+		assert.ErrorIs(t, err, ErrInvalidUnsubscribeToken, forged)
+	}
+
+	t.Setenv("UNSUBSCRIBE_SECRET", "another-secret")
+	_, _, err = ParseUnsubscribeToken(token)
+	assert.ErrorIs(t, err, ErrInvalidUnsubscribeToken)
+}
+
+func TestUnsubscribeTokenRequiresSecret(t *testing.T) {
+	t.Setenv("UNSUBSCRIBE_SECRET", "")
+
+	_, err := GenerateUnsubscribeToken(42, "news_published", time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, ErrUnsubscribeSecretMissing)
+	_, _, err = ParseUnsubscribeToken("payload.signature")
+	assert.ErrorIs(t, err, ErrUnsubscribeSecretMissing)
+}